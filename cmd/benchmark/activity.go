@@ -0,0 +1,150 @@
+// cmd/benchmark/activity.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/models"
+	gorm_repo "go-db-performance-study/internal/repository/gorm"
+)
+
+// activitySources 比較する集計方法
+var activitySources = []models.ActivitySource{models.ActivityLive, models.ActivityRollup}
+
+// runActivity コメント活動統計を comments のその場の集計と日別の事前集計で比較
+//
+// 期間は最後のコメントの日までの -days 日間。事前集計が空なら先に作る（-refresh で常に作り直す）。
+func runActivity(args []string) error {
+	fs := flag.NewFlagSet("activity", flag.ExitOnError)
+	var (
+		env        = fs.String("env", "testing", "環境 (development/testing)。事前に generate-data でデータを作成しておく")
+		days       = fs.Int("days", 30, "集計する期間（最後のコメントの日までの日数）")
+		top        = fs.Int("top", 10, "コメント数の多いユーザーの取得件数")
+		users      = fs.Int("users", 100, "1ユーザーの集計を実行するユーザー数")
+		iterations = fs.Int("iterations", 5, "上位ユーザーの集計の繰り返し回数")
+		seed       = fs.Int64("seed", 42, "ユーザーを選ぶ乱数シード")
+		refresh    = fs.Bool("refresh", false, "事前集計を作り直してから比較する")
+	)
+	fs.Parse(args)
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	repo := gorm_repo.NewCommentRepository(db)
+
+	var last *time.Time
+	if err := db.Model(&models.Comment{}).Select("MAX(created_at)").Scan(&last).Error; err != nil {
+		return fmt.Errorf("最後のコメントの取得エラー: %w", err)
+	}
+	if last == nil {
+		return fmt.Errorf("コメントがありません（generate-data でデータを作成してください）")
+	}
+	to := models.StartOfDay(*last).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -*days)
+
+	var rollupRows int64
+	if err := db.Model(&models.CommentDailyRollup{}).Where("day >= ? AND day < ?", from, to).Count(&rollupRows).Error; err != nil {
+		return fmt.Errorf("事前集計の件数取得エラー: %w", err)
+	}
+	if *refresh || rollupRows == 0 {
+		log.Printf("事前集計を作成中: %s 〜 %s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+		start := time.Now()
+		n, rows, err := repo.RefreshActivityRollup(from, to)
+		if err != nil {
+			return err
+		}
+		rollupRows = rows
+		log.Printf("事前集計: %d日分・%d行 (%v)", n, rows, time.Since(start).Round(time.Millisecond))
+	}
+
+	// 期間内にコメントしたユーザーから選ぶ
+	var candidates []uint
+	err = db.Model(&models.CommentDailyRollup{}).
+		Where("day >= ? AND day < ?", from, to).
+		Distinct("user_id").Order("user_id").
+		Pluck("user_id", &candidates).Error
+	if err != nil {
+		return fmt.Errorf("ユーザーの取得エラー: %w", err)
+	}
+	r := rand.New(rand.NewSource(*seed))
+	var sample []uint
+	for i := 0; i < *users && len(candidates) > 0; i++ {
+		sample = append(sample, candidates[r.Intn(len(candidates))])
+	}
+
+	fmt.Printf("\n=== コメント活動統計（%s 〜 %s, 事前集計 %d行）===\n",
+		from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"), rollupRows)
+	fmt.Printf("%-8s %18s %18s\n", "方法", fmt.Sprintf("上位%d人 (ms/回)", *top), "1ユーザー (ms/回)")
+
+	topResults := map[models.ActivitySource][]models.UserCommentActivity{}
+	userResults := map[models.ActivitySource][]int{}
+	for _, source := range activitySources {
+		start := time.Now()
+		for i := 0; i < *iterations; i++ {
+			if topResults[source], err = repo.GetTopCommenters(from, to, *top, source); err != nil {
+				return err
+			}
+		}
+		topTime := time.Since(start) / time.Duration(max(*iterations, 1))
+
+		start = time.Now()
+		for _, id := range sample {
+			a, err := repo.GetUserCommentActivity(id, from, to, source)
+			if err != nil {
+				return err
+			}
+			userResults[source] = append(userResults[source], a.CommentCount)
+		}
+		userTime := time.Since(start) / time.Duration(max(len(sample), 1))
+
+		fmt.Printf("%-8s %18.2f %18.2f\n", source, ms(topTime), ms(userTime))
+	}
+
+	printActivityCheck(topResults, userResults)
+	return nil
+}
+
+// printActivityCheck 集計方法によって結果が変わらないか確認
+func printActivityCheck(top map[models.ActivitySource][]models.UserCommentActivity, users map[models.ActivitySource][]int) {
+	live, rollup := top[models.ActivityLive], top[models.ActivityRollup]
+	same := len(live) == len(rollup)
+	for i := 0; same && i < len(live); i++ {
+		same = live[i].UserID == rollup[i].UserID && live[i].CommentCount == rollup[i].CommentCount
+	}
+	for i := range users[models.ActivityLive] {
+		if same && users[models.ActivityLive][i] != users[models.ActivityRollup][i] {
+			same = false
+		}
+	}
+	if same {
+		fmt.Printf("\n結果: 一致\n")
+		return
+	}
+	fmt.Printf("\n結果: 不一致（事前集計が古い可能性があります。-refresh で作り直してください）\n")
+	printTopCommenters("live", live)
+	printTopCommenters("rollup", rollup)
+}
+
+// printTopCommenters 上位ユーザーを出力
+func printTopCommenters(label string, activity []models.UserCommentActivity) {
+	fmt.Printf("  %s:\n", label)
+	for _, a := range activity {
+		fmt.Printf("    #%-8d %-20s %6d件 平均 %6.1f文字 スパム率 %5.1f%%\n",
+			a.UserID, a.UserName, a.CommentCount, a.AvgLength, a.SpamRatio*100)
+	}
+}
+
+// ms 時間をミリ秒で
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,200 @@
+// cmd/benchmark/iprep.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/ipreputation"
+)
+
+// runIPRep IP リストの引き方（2分木の最長一致 / 全件の線形探索）ごとの1件あたりの時間を比較
+//
+// データベースは使わない。-allow-files / -deny-files を指定しなければ乱数で作ったプレフィックスを使う。
+func runIPRep(args []string) error {
+	fs := flag.NewFlagSet("iprep", flag.ExitOnError)
+	var (
+		prefixes      = fs.Int("prefixes", 10000, "乱数で作るプレフィックス数")
+		v6Ratio       = fs.Float64("v6", 0.3, "IPv6 の割合（プレフィックス・引くアドレスの両方）")
+		denyRatio     = fs.Float64("deny-ratio", 0.8, "拒否リストの割合（残りは許可リスト）")
+		lookups       = fs.Int("lookups", 1000000, "2分木で引くアドレス数")
+		linearLookups = fs.Int("linear-lookups", 10000, "線形探索で引くアドレス数（遅いので少なめ）")
+		hitRatio      = fs.Float64("hit", 0.5, "いずれかのプレフィックスの範囲から選ぶアドレスの割合")
+		seed          = fs.Int64("seed", 42, "乱数シード")
+		allowFiles    = fs.String("allow-files", "", "許可リストのファイル（カンマ区切り。指定すると乱数のプレフィックスの代わりに使う）")
+		denyFiles     = fs.String("deny-files", "", "拒否リストのファイル（カンマ区切り）")
+	)
+	fs.Parse(args)
+
+	r := rand.New(rand.NewSource(*seed))
+
+	var entries []ipreputation.Entry
+	if *allowFiles != "" || *denyFiles != "" {
+		lists := []struct {
+			files   string
+			verdict ipreputation.Verdict
+		}{
+			{*allowFiles, ipreputation.Allow},
+			{*denyFiles, ipreputation.Deny},
+		}
+		for _, l := range lists {
+			for _, path := range splitList(l.files) {
+				e, err := ipreputation.ReadList(path, l.verdict)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, e...)
+			}
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("リストのファイルにプレフィックスがありません")
+		}
+	} else {
+		for i := 0; i < *prefixes; i++ {
+			verdict := ipreputation.Allow
+			if r.Float64() < *denyRatio {
+				verdict = ipreputation.Deny
+			}
+			entries = append(entries, ipreputation.Entry{
+				Prefix:  randomPrefix(r, r.Float64() < *v6Ratio),
+				Verdict: verdict,
+				Source:  fmt.Sprintf("random:%d", i+1),
+			})
+		}
+	}
+
+	start := time.Now()
+	table := ipreputation.NewTable(entries)
+	buildTime := time.Since(start)
+
+	n := *lookups
+	if *linearLookups > n {
+		n = *linearLookups
+	}
+	addrs := make([]netip.Addr, n)
+	for i := range addrs {
+		if r.Float64() < *hitRatio {
+			addrs[i] = randomAddrIn(r, entries[r.Intn(len(entries))].Prefix)
+		} else {
+			addrs[i] = randomAddr(r, r.Float64() < *v6Ratio)
+		}
+	}
+
+	fmt.Printf("\n=== IP リストの引き方の比較 ===\n")
+	fmt.Printf("プレフィックス: %d件（重複を除いて %d件）, 表の作成: %v\n", len(entries), table.Len(), buildTime.Round(time.Microsecond))
+	fmt.Printf("%-8s %12s %14s %10s %10s\n", "方法", "件数", "ns/件", "一致", "拒否")
+
+	trie := func(a netip.Addr) (ipreputation.Entry, bool) { return table.Lookup(a) }
+	linear := func(a netip.Addr) (ipreputation.Entry, bool) { return linearLookup(entries, a) }
+	printIPRepResult("trie", addrs[:*lookups], trie)
+	printIPRepResult("linear", addrs[:*linearLookups], linear)
+
+	// 両方の方法で同じ評価になるか確認
+	mismatches := 0
+	for _, a := range addrs[:*linearLookups] {
+		e1, ok1 := trie(a)
+		e2, ok2 := linear(a)
+		if ok1 != ok2 || e1.Verdict != e2.Verdict || e1.Prefix != e2.Prefix {
+			mismatches++
+		}
+	}
+	if mismatches == 0 {
+		fmt.Printf("\n評価: %d件で一致\n", *linearLookups)
+	} else {
+		fmt.Printf("\n評価: %d件中 %d件が不一致\n", *linearLookups, mismatches)
+	}
+	return nil
+}
+
+// printIPRepResult addrs を引いた時間と一致・拒否の件数を出力
+func printIPRepResult(name string, addrs []netip.Addr, lookup func(netip.Addr) (ipreputation.Entry, bool)) {
+	if len(addrs) == 0 {
+		return
+	}
+	var matched, denied int
+	start := time.Now()
+	for _, a := range addrs {
+		if e, ok := lookup(a); ok {
+			matched++
+			if e.Verdict == ipreputation.Deny {
+				denied++
+			}
+		}
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("%-8s %12d %14.1f %10d %10d\n", name, len(addrs), float64(elapsed.Nanoseconds())/float64(len(addrs)), matched, denied)
+}
+
+// linearLookup 全てのエントリを調べて最も長いプレフィックスを選ぶ（同じプレフィックスは拒否を優先）
+func linearLookup(entries []ipreputation.Entry, addr netip.Addr) (ipreputation.Entry, bool) {
+	addr = addr.Unmap()
+	var found ipreputation.Entry
+	ok := false
+	for _, e := range entries {
+		if !e.Prefix.Contains(addr) {
+			continue
+		}
+		if !ok || e.Prefix.Bits() > found.Prefix.Bits() ||
+			(e.Prefix.Bits() == found.Prefix.Bits() && e.Verdict == ipreputation.Deny) {
+			found, ok = e, true
+		}
+	}
+	return found, ok
+}
+
+// randomPrefix 乱数のプレフィックス（IPv4 は /8〜/32、IPv6 は /16〜/128）
+func randomPrefix(r *rand.Rand, v6 bool) netip.Prefix {
+	addr := randomAddr(r, v6)
+	bits := 8 + r.Intn(25)
+	if v6 {
+		bits = 16 + r.Intn(113)
+	}
+	return netip.PrefixFrom(addr, bits).Masked()
+}
+
+// randomAddr 乱数のアドレス
+func randomAddr(r *rand.Rand, v6 bool) netip.Addr {
+	if v6 {
+		var b [16]byte
+		r.Read(b[:])
+		return netip.AddrFrom16(b)
+	}
+	var b [4]byte
+	r.Read(b[:])
+	return netip.AddrFrom4(b)
+}
+
+// randomAddrIn プレフィックスの範囲内の乱数のアドレス
+func randomAddrIn(r *rand.Rand, p netip.Prefix) netip.Addr {
+	base := p.Addr().AsSlice()
+	host := randomAddr(r, p.Addr().Is6()).AsSlice()
+	for i := range base {
+		// 先頭 Bits() ビットはプレフィックス、残りは乱数
+		keep := p.Bits() - i*8
+		switch {
+		case keep >= 8:
+		case keep <= 0:
+			base[i] = host[i]
+		default:
+			mask := byte(0xff) << (8 - keep)
+			base[i] = base[i]&mask | host[i]&^mask
+		}
+	}
+	addr, _ := netip.AddrFromSlice(base)
+	return addr
+}
+
+// splitList カンマ区切りの一覧（空の要素は除く）
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
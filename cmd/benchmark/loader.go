@@ -0,0 +1,173 @@
+// cmd/benchmark/loader.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/password"
+	"go-db-performance-study/internal/testdata"
+
+	"gorm.io/gorm"
+)
+
+// loaderCase 比較する書き込み方法の組み合わせ
+type loaderCase struct {
+	name           string
+	method         testdata.InsertMethod
+	disableIndexes bool
+	disableChecks  bool
+}
+
+var loaderCases = map[string]loaderCase{
+	"insert":         {name: "insert", method: testdata.InsertMethodBatch},
+	"insert-fast":    {name: "insert-fast", method: testdata.InsertMethodBatch, disableIndexes: true, disableChecks: true},
+	"load-data":      {name: "load-data", method: testdata.InsertMethodLoadData},
+	"load-data-fast": {name: "load-data-fast", method: testdata.InsertMethodLoadData, disableIndexes: true, disableChecks: true},
+}
+
+// loaderResult 1ケース分の結果
+type loaderResult struct {
+	name     string
+	stats    []testdata.PhaseStat
+	elapsed  time.Duration
+	checksum string
+}
+
+// runLoader 書き込み方法ごとに同じシードのデータセットを生成し、テーブル別の挿入速度を比較
+func runLoader(args []string) error {
+	fs := flag.NewFlagSet("loader", flag.ExitOnError)
+	var (
+		env      = fs.String("env", "testing", "環境 (development/testing)。各ケースの前に全データを削除するので注意")
+		users    = fs.Int("users", 5000, "ユーザー数（パスワードは fast でハッシュ化し、書き込み方法の差だけを測る）")
+		posts    = fs.Int("posts", 5000, "投稿数")
+		tags     = fs.Int("tags", 50, "タグ数")
+		comments = fs.Int("comments", 50000, "コメント数")
+		batch    = fs.Int("batch", 1000, "バッチサイズ")
+		workers  = fs.Int("workers", 1, "ワーカー数")
+		seed     = fs.Int64("seed", 42, "乱数シード（全ケースで同じデータを生成する）")
+		methods  = fs.String("methods", "insert,load-data,load-data-fast", "比較するケース (insert/insert-fast/load-data/load-data-fast)")
+	)
+	fs.Parse(args)
+
+	var cases []loaderCase
+	for _, name := range strings.Split(*methods, ",") {
+		c, ok := loaderCases[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("未知のケース: %s", name)
+		}
+		cases = append(cases, c)
+	}
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+
+	hasher, err := password.New(password.Config{Algorithm: password.AlgorithmFast})
+	if err != nil {
+		return err
+	}
+	password.SetDefault(hasher)
+
+	var results []loaderResult
+	for _, c := range cases {
+		log.Printf("=== ケース: %s ===", c.name)
+		if err := resetTables(db); err != nil {
+			return err
+		}
+
+		generator := testdata.NewDataGenerator(db, testdata.GeneratorConfig{
+			UserCount:      *users,
+			PostCount:      *posts,
+			TagCount:       *tags,
+			CommentCount:   *comments,
+			BatchSize:      *batch,
+			Seed:           *seed,
+			Workers:        *workers,
+			InsertMethod:   c.method,
+			DisableIndexes: c.disableIndexes,
+			DisableChecks:  c.disableChecks,
+
+			ReplyProbability: 0.3,
+			MaxReplyDepth:    4,
+		})
+
+		start := time.Now()
+		if err := generator.GenerateAll(); err != nil {
+			return fmt.Errorf("ケース %s: %w", c.name, err)
+		}
+		elapsed := time.Since(start)
+
+		sum, err := testdata.ComputeChecksum(db)
+		if err != nil {
+			return err
+		}
+		results = append(results, loaderResult{name: c.name, stats: generator.Stats(), elapsed: elapsed, checksum: sum.Checksum})
+	}
+
+	printLoaderResults(results)
+	return nil
+}
+
+// resetTables 生成データとチェックポイントを削除
+func resetTables(db *gorm.DB) error {
+	_, err := testdata.Cleanup(db, testdata.CleanupOptions{Mode: testdata.CleanupTruncate})
+	return err
+}
+
+// printLoaderResults テーブル別の挿入速度（行/秒）を表形式で出力
+func printLoaderResults(results []loaderResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Printf("\n=== テーブル別の挿入速度（行/秒）===\n")
+	fmt.Printf("%-12s", "テーブル")
+	for _, r := range results {
+		fmt.Printf(" %16s", r.name)
+	}
+	fmt.Println()
+
+	for i, stat := range results[0].stats {
+		fmt.Printf("%-12s", stat.Phase)
+		for _, r := range results {
+			if i < len(r.stats) {
+				fmt.Printf(" %16.0f", r.stats[i].RowsPerSecond())
+			} else {
+				fmt.Printf(" %16s", "-")
+			}
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("%-12s", "合計時間")
+	for _, r := range results {
+		fmt.Printf(" %16v", r.elapsed.Round(time.Millisecond))
+	}
+	fmt.Println()
+
+	// 書き込み方法が違っても同じデータになっているか確認
+	same := true
+	for _, r := range results[1:] {
+		if r.checksum != results[0].checksum {
+			same = false
+		}
+	}
+	if same {
+		fmt.Printf("\nチェックサム: 全ケース一致 (%s)\n", results[0].checksum[:16])
+	} else {
+		fmt.Printf("\nチェックサム: 不一致\n")
+		for _, r := range results {
+			fmt.Printf("  %-16s %s\n", r.name, r.checksum)
+		}
+	}
+}
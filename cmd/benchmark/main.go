@@ -0,0 +1,54 @@
+// cmd/benchmark/main.go
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// subcommand ベンチマークの種類
+type subcommand struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var subcommands = []subcommand{
+	{"loader", "書き込み方法（複数行 INSERT / LOAD DATA）ごとのテーブル別挿入速度を比較", runLoader},
+	{"moderation", "承認待ちコメントの承認を監査ログの有無・一括か1件ずつかで比較（書き込み行数）", runModeration},
+	{"activity", "コメント活動統計（上位ユーザー・1ユーザー）をその場の集計と日別の事前集計で比較", runActivity},
+	{"thread", "スレッドの読み込みと段数の計算を parent_id の反復・WITH RECURSIVE・マテリアライズドパスで比較", runThread},
+	{"password", "パスワードハッシュの方式・パラメータ（bcrypt の cost / argon2id / fast）ごとのハッシュ化と照合の時間を比較", runPassword},
+	{"iprep", "IP リストの引き方（2分木の最長一致 / 線形探索）ごとの1件あたりの時間を比較", runIPRep},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, sc := range subcommands {
+		if sc.name == name {
+			if err := sc.run(os.Args[2:]); err != nil {
+				log.Fatalf("%s ベンチマークエラー: %v", name, err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "未知のサブコマンド: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage 使い方を表示
+func usage() {
+	fmt.Fprintf(os.Stderr, "使い方: benchmark <サブコマンド> [フラグ]\n\nサブコマンド:\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", sc.name, sc.description)
+	}
+	fmt.Fprintf(os.Stderr, "\n各サブコマンドのフラグは benchmark <サブコマンド> -h で確認できます\n")
+}
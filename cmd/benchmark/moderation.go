@@ -0,0 +1,161 @@
+// cmd/benchmark/moderation.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/models"
+	gorm_repo "go-db-performance-study/internal/repository/gorm"
+
+	"gorm.io/gorm"
+)
+
+// moderationCase 比較するモデレーションの方法
+type moderationCase struct {
+	name    string
+	audited bool // 監査ログを残すか
+	run     func(db *gorm.DB, ids []uint, moderatorID uint) error
+}
+
+var moderationCases = map[string]moderationCase{
+	// 監査ログなしでステータスだけを一括更新（監査ログの前の方法）
+	"plain": {name: "plain", run: func(db *gorm.DB, ids []uint, _ uint) error {
+		return forChunks(ids, 1000, func(chunk []uint) error {
+			return db.Model(&models.Comment{}).
+				Where("id IN ? AND status = ?", chunk, models.CommentStatusPending).
+				Update("status", models.CommentStatusApproved).Error
+		})
+	}},
+	// リポジトリの一括承認（行ロック・UPDATE・監査ログの INSERT）
+	"audited": {name: "audited", audited: true, run: func(db *gorm.DB, ids []uint, moderatorID uint) error {
+		_, err := gorm_repo.NewCommentRepository(db).ApprovePending(ids, moderatorID, "benchmark")
+		return err
+	}},
+	// 1件ずつ Comment.Approve（1件ごとにトランザクション）
+	"per-row": {name: "per-row", audited: true, run: func(db *gorm.DB, ids []uint, moderatorID uint) error {
+		for _, id := range ids {
+			c := models.Comment{ID: id, Status: models.CommentStatusPending}
+			if err := c.Approve(db, moderatorID, "benchmark"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+}
+
+// runModeration 監査ログの有無・一括か1件ずつかで、承認待ちコメントの承認速度と書き込み行数を比較
+//
+// 既存のコメントを承認待ちに戻してから各ケースを実行し、最後に元のステータスへ戻す。
+func runModeration(args []string) error {
+	fs := flag.NewFlagSet("moderation", flag.ExitOnError)
+	var (
+		env      = fs.String("env", "testing", "環境 (development/testing)。事前に generate-data でデータを作成しておく")
+		comments = fs.Int("comments", 10000, "承認するコメント数（ID の小さい順に選ぶ）")
+		cases    = fs.String("cases", "plain,audited,per-row", "比較するケース (plain/audited/per-row)")
+	)
+	fs.Parse(args)
+
+	var selected []moderationCase
+	for _, name := range strings.Split(*cases, ",") {
+		c, ok := moderationCases[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("未知のケース: %s", name)
+		}
+		selected = append(selected, c)
+	}
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+
+	var sample []models.Comment
+	if err := db.Select("id", "status").Order("id").Limit(*comments).Find(&sample).Error; err != nil {
+		return fmt.Errorf("コメントの取得エラー: %w", err)
+	}
+	if len(sample) == 0 {
+		return fmt.Errorf("コメントがありません（generate-data でデータを作成してください）")
+	}
+	var moderatorID uint
+	if err := db.Model(&models.User{}).Select("MIN(id)").Scan(&moderatorID).Error; err != nil {
+		return fmt.Errorf("モデレーターの取得エラー: %w", err)
+	}
+	ids := make([]uint, len(sample))
+	for i, c := range sample {
+		ids[i] = c.ID
+	}
+	defer restoreStatuses(db, sample)
+
+	fmt.Printf("\n=== 承認待ちコメントの承認（%d件）===\n", len(ids))
+	fmt.Printf("%-10s %12s %12s %14s %14s\n", "ケース", "時間", "件/秒", "監査ログ(行)", "書き込み行/件")
+	for _, c := range selected {
+		if err := resetPending(db, ids); err != nil {
+			return err
+		}
+
+		log.Printf("=== ケース: %s ===", c.name)
+		start := time.Now()
+		if err := c.run(db, ids, moderatorID); err != nil {
+			return fmt.Errorf("ケース %s: %w", c.name, err)
+		}
+		elapsed := time.Since(start)
+
+		var actions int64
+		if err := db.Model(&models.ModerationAction{}).Where("reason = ?", "benchmark").Count(&actions).Error; err != nil {
+			return fmt.Errorf("監査ログの件数取得エラー: %w", err)
+		}
+		fmt.Printf("%-10s %12v %12.0f %14d %14.2f\n", c.name, elapsed.Round(time.Millisecond),
+			float64(len(ids))/elapsed.Seconds(), actions, float64(int64(len(ids))+actions)/float64(len(ids)))
+	}
+
+	return resetPending(db, nil)
+}
+
+// resetPending ids のコメントを承認待ちに戻し、ベンチマークの監査ログを削除（ids が nil なら監査ログだけ）
+func resetPending(db *gorm.DB, ids []uint) error {
+	if err := db.Where("reason = ?", "benchmark").Delete(&models.ModerationAction{}).Error; err != nil {
+		return fmt.Errorf("監査ログの削除エラー: %w", err)
+	}
+	return forChunks(ids, 1000, func(chunk []uint) error {
+		return db.Model(&models.Comment{}).Where("id IN ?", chunk).Update("status", models.CommentStatusPending).Error
+	})
+}
+
+// restoreStatuses ベンチマーク前のステータスに戻す
+func restoreStatuses(db *gorm.DB, sample []models.Comment) {
+	byStatus := map[models.CommentStatus][]uint{}
+	for _, c := range sample {
+		byStatus[c.Status] = append(byStatus[c.Status], c.ID)
+	}
+	for status, ids := range byStatus {
+		err := forChunks(ids, 1000, func(chunk []uint) error {
+			return db.Model(&models.Comment{}).Where("id IN ?", chunk).Update("status", status).Error
+		})
+		if err != nil {
+			log.Printf("ステータスの復元エラー (%s): %v", status, err)
+		}
+	}
+}
+
+// forChunks ids を size 件ずつに分けて fn を呼ぶ
+func forChunks(ids []uint, size int, fn func(chunk []uint) error) error {
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := fn(ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
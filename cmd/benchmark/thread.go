@@ -0,0 +1,154 @@
+// cmd/benchmark/thread.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/models"
+	gorm_repo "go-db-performance-study/internal/repository/gorm"
+)
+
+// runThread スレッドの読み込みと段数の計算を読み込み方法ごとに比較
+//
+// 返信のあるルートコメントを選んでスレッド全体を読み込み、各スレッドの葉のコメントで段数を求める。
+// どの方法でも同じ結果になるかも確認する。
+func runThread(args []string) error {
+	fs := flag.NewFlagSet("thread", flag.ExitOnError)
+	var (
+		env        = fs.String("env", "testing", "環境 (development/testing)。事前に generate-data でデータを作成しておく")
+		threads    = fs.Int("threads", 100, "読み込むスレッド数（返信のあるルートコメントから選ぶ）")
+		strategies = fs.String("strategies", "adjacency,recursive,path", "比較する読み込み方法 (adjacency/recursive/path)")
+		seed       = fs.Int64("seed", 42, "スレッドを選ぶ乱数シード")
+	)
+	fs.Parse(args)
+
+	var selected []models.ThreadStrategy
+	for _, name := range strings.Split(*strategies, ",") {
+		s := models.ThreadStrategy(strings.TrimSpace(name))
+		known := false
+		for _, t := range models.ThreadStrategies {
+			known = known || s == t
+		}
+		if !known {
+			return fmt.Errorf("未知の読み込み方法: %s", name)
+		}
+		selected = append(selected, s)
+	}
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	repo := gorm_repo.NewCommentRepository(db)
+
+	for _, s := range selected {
+		if s != models.ThreadPath {
+			continue
+		}
+		var missing int64
+		if err := db.Model(&models.Comment{}).Where("path = ''").Count(&missing).Error; err != nil {
+			return fmt.Errorf("path の確認エラー: %w", err)
+		}
+		if missing > 0 {
+			return fmt.Errorf("path が空のコメントが %d件あります（maintenance backfill-comment-paths を実行してください）", missing)
+		}
+	}
+
+	var candidates []uint
+	err = db.Model(&models.Comment{}).
+		Where("parent_id IS NULL AND EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = comments.id)").
+		Order("id").
+		Pluck("id", &candidates).Error
+	if err != nil {
+		return fmt.Errorf("ルートコメントの取得エラー: %w", err)
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("返信のあるコメントがありません（generate-data でデータを作成してください）")
+	}
+	r := rand.New(rand.NewSource(*seed))
+	roots := make([]uint, 0, *threads)
+	for i := 0; i < *threads; i++ {
+		roots = append(roots, candidates[r.Intn(len(candidates))])
+	}
+
+	// 段数を求めるコメントは最初の方法で読み込んだスレッドの葉から選ぶ
+	var leaves []uint
+	results := map[models.ThreadStrategy][]string{}
+	var comments, maxDepth int
+
+	fmt.Printf("\n=== スレッドの読み込み（%dスレッド）===\n", len(roots))
+	fmt.Printf("%-10s %16s %16s\n", "方法", "スレッド (ms/件)", "段数 (ms/件)")
+	for _, strategy := range selected {
+		start := time.Now()
+		for _, id := range roots {
+			thread, err := repo.GetThread(id, strategy)
+			if err != nil {
+				return err
+			}
+			results[strategy] = append(results[strategy], threadSignature(thread))
+			if strategy == selected[0] {
+				comments += len(thread)
+				for i, c := range thread {
+					maxDepth = max(maxDepth, c.Depth)
+					if i == len(thread)-1 || thread[i+1].Depth <= c.Depth {
+						leaves = append(leaves, c.ID)
+					}
+				}
+			}
+		}
+		threadTime := time.Since(start) / time.Duration(max(len(roots), 1))
+
+		start = time.Now()
+		var depths []string
+		for _, id := range leaves {
+			depth, err := repo.GetDepth(id, strategy)
+			if err != nil {
+				return err
+			}
+			depths = append(depths, fmt.Sprintf("%d:%d", id, depth))
+		}
+		depthTime := time.Since(start) / time.Duration(max(len(leaves), 1))
+		results[strategy] = append(results[strategy], strings.Join(depths, ","))
+
+		fmt.Printf("%-10s %16.2f %16.2f\n", strategy, ms(threadTime), ms(depthTime))
+	}
+	fmt.Printf("\nコメント %d件（平均 %.1f件/スレッド、最大 %d段）、段数を求めたコメント %d件\n",
+		comments, float64(comments)/float64(len(roots)), maxDepth, len(leaves))
+
+	same := true
+	for _, strategy := range selected[1:] {
+		for i := range results[strategy] {
+			if results[strategy][i] != results[selected[0]][i] {
+				same = false
+				if i < len(roots) {
+					fmt.Printf("不一致: %s と %s（スレッド #%d）\n", selected[0], strategy, roots[i])
+				} else {
+					fmt.Printf("不一致: %s と %s（段数）\n", selected[0], strategy)
+				}
+				break
+			}
+		}
+	}
+	if same {
+		fmt.Printf("結果: 一致\n")
+	}
+	return nil
+}
+
+// threadSignature スレッドの並び順と段数を比較用の文字列にする
+func threadSignature(thread []models.ThreadComment) string {
+	var b strings.Builder
+	for _, c := range thread {
+		fmt.Fprintf(&b, "%d:%d,", c.ID, c.Depth)
+	}
+	return b.String()
+}
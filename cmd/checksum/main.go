@@ -0,0 +1,109 @@
+// cmd/checksum/main.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/testdata"
+)
+
+func main() {
+	var (
+		env     = flag.String("env", "development", "環境 (development/testing)")
+		against = flag.String("against", "", "比較対象の環境（指定時は2つのデータセットを比較）")
+		expect  = flag.String("expect", "", "比較対象のチェックサムJSONファイル")
+		output  = flag.String("out", "", "チェックサムをJSONで保存するファイル")
+	)
+	flag.Parse()
+
+	log.Printf("=== データセットチェックサム ===")
+
+	current := compute(*env)
+	printChecksum(*env, current)
+
+	if *output != "" {
+		data, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			log.Fatalf("JSON変換エラー: %v", err)
+		}
+		if err := os.WriteFile(*output, data, 0o644); err != nil {
+			log.Fatalf("ファイル書き込みエラー: %v", err)
+		}
+		log.Printf("チェックサムを保存しました: %s", *output)
+	}
+
+	var other *testdata.DatasetChecksum
+	label := ""
+	switch {
+	case *against != "":
+		other = compute(*against)
+		label = *against
+		printChecksum(*against, other)
+	case *expect != "":
+		data, err := os.ReadFile(*expect)
+		if err != nil {
+			log.Fatalf("ファイル読み込みエラー: %v", err)
+		}
+		other = &testdata.DatasetChecksum{}
+		if err := json.Unmarshal(data, other); err != nil {
+			log.Fatalf("JSON解析エラー: %v", err)
+		}
+		label = *expect
+	default:
+		return
+	}
+
+	if !compare(current, other) {
+		log.Printf("❌ データセットが一致しません (%s vs %s)", *env, label)
+		os.Exit(1)
+	}
+	log.Printf("✅ データセットは同一です (%s vs %s)", *env, label)
+}
+
+// compute 指定環境のチェックサムを計算
+func compute(env string) *testdata.DatasetChecksum {
+	db, err := database.Connect(env)
+	if err != nil {
+		log.Fatalf("データベース接続エラー: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("SQL DB取得エラー: %v", err)
+	}
+	defer sqlDB.Close()
+
+	checksum, err := testdata.ComputeChecksum(db)
+	if err != nil {
+		log.Fatalf("チェックサム計算エラー: %v", err)
+	}
+	return checksum
+}
+
+// printChecksum チェックサムを表示
+func printChecksum(env string, c *testdata.DatasetChecksum) {
+	log.Printf("[%s]", env)
+	for _, t := range c.Tables {
+		log.Printf("  %-10s %10d行  %s", t.Table, t.Rows, t.Checksum)
+	}
+	log.Printf("  全体: %s", c.Checksum)
+}
+
+// compare テーブルごとの差分を表示しつつ比較
+func compare(a, b *testdata.DatasetChecksum) bool {
+	others := make(map[string]testdata.TableChecksum, len(b.Tables))
+	for _, t := range b.Tables {
+		others[t.Table] = t
+	}
+	for _, t := range a.Tables {
+		o, ok := others[t.Table]
+		if !ok || o.Checksum != t.Checksum {
+			log.Printf("  差分あり: %s (%d行 / %d行)", t.Table, t.Rows, o.Rows)
+		}
+	}
+	return a.Equal(b)
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"go-db-performance-study/internal/account"
 	"go-db-performance-study/internal/database"
 	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/password"
 	gorm_repo "go-db-performance-study/internal/repository/gorm"
 	"go-db-performance-study/internal/repository/interfaces"
+
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -24,17 +29,39 @@ func main() {
 	// リポジトリ作成
 	userRepo := gorm_repo.NewUserRepository(db)
 	postRepo := gorm_repo.NewPostRepository(db)
+	tagRepo := gorm_repo.NewTagRepository(db)
+	commentRepo := gorm_repo.NewCommentRepository(db)
 
 	// ユーザーCRUDテスト
 	if err := testUserCRUD(userRepo); err != nil {
 		log.Fatalf("ユーザーCRUDテストエラー: %v", err)
 	}
 
+	// メール認証・ログイン保持トークンのテスト
+	if err := testUserTokens(userRepo); err != nil {
+		log.Fatalf("トークンのテストエラー: %v", err)
+	}
+
+	// パスワードの照合と再ハッシュのテスト
+	if err := testPasswordRehash(db, userRepo); err != nil {
+		log.Fatalf("パスワードのテストエラー: %v", err)
+	}
+
 	// 投稿CRUDテスト
 	if err := testPostCRUD(postRepo, userRepo); err != nil {
 		log.Fatalf("投稿CRUDテストエラー: %v", err)
 	}
 
+	// タグCRUDテスト
+	if err := testTagCRUD(tagRepo); err != nil {
+		log.Fatalf("タグCRUDテストエラー: %v", err)
+	}
+
+	// コメントのモデレーションテスト
+	if err := testCommentModeration(commentRepo, postRepo, userRepo); err != nil {
+		log.Fatalf("コメントのモデレーションテストエラー: %v", err)
+	}
+
 	log.Println("=== 全テスト完了 ===")
 }
 
@@ -95,6 +122,108 @@ func testUserCRUD(repo interfaces.UserRepository) error {
 	return nil
 }
 
+func testUserTokens(repo interfaces.UserRepository) error {
+	log.Println("--- メール認証・ログイン保持トークンのテスト ---")
+
+	service, err := account.NewService(repo, account.DefaultConfig())
+	if err != nil {
+		return err
+	}
+	user := &models.User{
+		Name:     "トークンテストユーザー",
+		Email:    fmt.Sprintf("token-test-%d@example.com", time.Now().UnixNano()),
+		Password: "password123",
+	}
+	if err := repo.Create(user); err != nil {
+		return fmt.Errorf("ユーザー作成エラー: %w", err)
+	}
+	defer repo.Delete(user.ID)
+
+	// メール認証（トークンは1回だけ使える）
+	token, err := service.IssueVerificationToken(user.ID)
+	if err != nil {
+		return fmt.Errorf("メール認証トークン発行エラー: %w", err)
+	}
+	verified, err := service.VerifyEmail(token)
+	if err != nil {
+		return fmt.Errorf("メール認証エラー: %w", err)
+	}
+	if !verified.IsEmailVerified() {
+		return fmt.Errorf("メール認証後も未認証のままです: ID=%d", user.ID)
+	}
+	if _, err := service.VerifyEmail(token); !errors.Is(err, account.ErrInvalidToken) {
+		return fmt.Errorf("使用済みのメール認証トークンが使えました: %v", err)
+	}
+	log.Printf("✅ メール認証: EmailVerifiedAt=%s", verified.EmailVerifiedAt.Format(time.RFC3339))
+
+	// ログイン保持（使うたびに新しいトークンに置き換わる）
+	remember, err := service.IssueRememberToken(user.ID)
+	if err != nil {
+		return fmt.Errorf("ログイン保持トークン発行エラー: %w", err)
+	}
+	authenticated, rotated, err := service.ConsumeRememberToken(remember)
+	if err != nil {
+		return fmt.Errorf("ログイン保持トークンでの認証エラー: %w", err)
+	}
+	if authenticated.ID != user.ID || rotated == remember {
+		return fmt.Errorf("ログイン保持トークンが置き換わっていません: ID=%d", authenticated.ID)
+	}
+	if _, _, err := service.ConsumeRememberToken(remember); !errors.Is(err, account.ErrInvalidToken) {
+		return fmt.Errorf("置き換え前のログイン保持トークンが使えました: %v", err)
+	}
+	if err := service.ForgetRememberToken(user.ID); err != nil {
+		return fmt.Errorf("ログイン保持トークン削除エラー: %w", err)
+	}
+	if _, _, err := service.ConsumeRememberToken(rotated); !errors.Is(err, account.ErrInvalidToken) {
+		return fmt.Errorf("削除したログイン保持トークンが使えました: %v", err)
+	}
+	log.Printf("✅ ログイン保持トークン: 発行・置き換え・削除")
+
+	return nil
+}
+
+func testPasswordRehash(db *gorm.DB, repo interfaces.UserRepository) error {
+	log.Println("--- パスワードの照合と再ハッシュのテスト ---")
+
+	user := &models.User{
+		Name:     "パスワードテストユーザー",
+		Email:    fmt.Sprintf("password-test-%d@example.com", time.Now().UnixNano()),
+		Password: "password123",
+	}
+	if err := repo.Create(user); err != nil {
+		return fmt.Errorf("ユーザー作成エラー: %w", err)
+	}
+	defer repo.Delete(user.ID)
+	before, _ := password.Identify(user.Password)
+
+	if ok, err := user.CheckPassword(db, "wrong-password"); ok || err != nil {
+		return fmt.Errorf("違うパスワードが一致しました: %v", err)
+	}
+
+	// 既定の方式を変えると、一致したときに新しい方式で作り直される
+	previous := password.Default()
+	defer password.SetDefault(previous)
+	fast, err := password.New(password.Config{Algorithm: password.AlgorithmFast})
+	if err != nil {
+		return err
+	}
+	password.SetDefault(fast)
+	if ok, err := user.CheckPassword(db, "password123"); !ok || err != nil {
+		return fmt.Errorf("パスワードが一致しません: %v", err)
+	}
+
+	saved, err := repo.GetByID(user.ID)
+	if err != nil {
+		return fmt.Errorf("ユーザー取得エラー: %w", err)
+	}
+	if after, _ := password.Identify(saved.Password); after != password.AlgorithmFast {
+		return fmt.Errorf("パスワードが再ハッシュされていません: %s", after)
+	}
+	log.Printf("✅ パスワードの再ハッシュ: %s → %s", before, password.AlgorithmFast)
+
+	return nil
+}
+
 func testPostCRUD(postRepo interfaces.PostRepository, userRepo interfaces.UserRepository) error {
 	log.Println("--- 投稿CRUDテスト ---")
 
@@ -154,6 +283,124 @@ func testPostCRUD(postRepo interfaces.PostRepository, userRepo interfaces.UserRe
 	return nil
 }
 
+func testTagCRUD(repo interfaces.TagRepository) error {
+	log.Println("--- タグCRUDテスト ---")
+
+	// Create（名前をユニークに）
+	tag := &models.Tag{Name: fmt.Sprintf("CRUDテスト %d", time.Now().UnixNano())}
+	if err := repo.Create(tag); err != nil {
+		return fmt.Errorf("タグ作成エラー: %w", err)
+	}
+	log.Printf("✅ タグ作成: ID=%d, Slug=%s", tag.ID, tag.Slug)
+
+	// Update（名前を変えると slug も変わる）
+	oldSlug := tag.Slug
+	if err := repo.Update(tag.ID, &models.TagForUpdate{Name: ptr(tag.Name + " 改")}); err != nil {
+		return fmt.Errorf("タグ更新エラー: %w", err)
+	}
+	updated, err := repo.GetByID(tag.ID)
+	if err != nil {
+		return fmt.Errorf("タグ取得エラー: %w", err)
+	}
+	log.Printf("✅ タグ更新: Slug=%s → %s", oldSlug, updated.Slug)
+
+	// 旧 slug から現在のタグに辿れるか
+	found, moved, err := repo.GetBySlug(oldSlug)
+	if err != nil {
+		return fmt.Errorf("旧スラッグでのタグ取得エラー: %w", err)
+	}
+	if found.ID != tag.ID || !moved {
+		return fmt.Errorf("旧スラッグ %s から現在のタグに辿れません: ID=%d, moved=%v", oldSlug, found.ID, moved)
+	}
+	log.Printf("✅ 旧スラッグでのタグ取得: %s → %s", oldSlug, found.Slug)
+
+	// Delete
+	if err := repo.Delete(tag.ID); err != nil {
+		return fmt.Errorf("タグ削除エラー: %w", err)
+	}
+	log.Printf("✅ タグ削除完了")
+
+	return nil
+}
+
+func testCommentModeration(commentRepo interfaces.CommentRepository, postRepo interfaces.PostRepository, userRepo interfaces.UserRepository) error {
+	log.Println("--- コメントのモデレーションテスト ---")
+
+	// テスト用ユーザー・投稿取得
+	users, err := userRepo.List(1, 0)
+	if err != nil || len(users) == 0 {
+		return fmt.Errorf("テスト用ユーザーが見つかりません")
+	}
+	posts, err := postRepo.List(1, 0)
+	if err != nil || len(posts) == 0 {
+		return fmt.Errorf("テスト用投稿が見つかりません")
+	}
+	moderatorID := users[0].ID
+
+	// Create（承認待ちで作成）
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		comment := &models.Comment{
+			PostID: posts[0].ID,
+			UserID: moderatorID,
+			Body:   fmt.Sprintf("モデレーションのテストコメント %d", i+1),
+			Status: models.CommentStatusPending,
+		}
+		if err := commentRepo.Create(comment); err != nil {
+			return fmt.Errorf("コメント作成エラー: %w", err)
+		}
+		ids = append(ids, comment.ID)
+	}
+	log.Printf("✅ 承認待ちコメント作成: %v", ids)
+
+	// 一括承認・一括スパム
+	approved, err := commentRepo.ApprovePending(ids[:2], moderatorID, "CRUDテスト")
+	if err != nil {
+		return fmt.Errorf("一括承認エラー: %w", err)
+	}
+	rejected, err := commentRepo.RejectPending(ids, moderatorID, "CRUDテスト")
+	if err != nil {
+		return fmt.Errorf("一括スパムエラー: %w", err)
+	}
+	if len(approved) != 2 || len(rejected) != 1 {
+		return fmt.Errorf("承認待ちでないコメントが変更されました: 承認=%v, スパム=%v", approved, rejected)
+	}
+	log.Printf("✅ 一括承認: %v, 一括スパム: %v（承認済みは飛ばす）", approved, rejected)
+
+	// 1件ずつ変更
+	if err := commentRepo.Moderate(ids[0], models.CommentStatusDeleted, moderatorID, "CRUDテスト"); err != nil {
+		return fmt.Errorf("コメントのステータス変更エラー: %w", err)
+	}
+
+	// 監査ログ
+	actions, err := commentRepo.ListModerationActions(ids[0])
+	if err != nil {
+		return fmt.Errorf("監査ログ取得エラー: %w", err)
+	}
+	if len(actions) != 2 {
+		return fmt.Errorf("監査ログの件数が違います: %d件（2件のはず）", len(actions))
+	}
+	for _, a := range actions {
+		log.Printf("✅ 監査ログ: %s → %s (%s)", a.FromStatus, a.ToStatus, a.Reason)
+	}
+
+	activity, err := commentRepo.GetModeratorActivity(time.Now().Add(-time.Hour), time.Now().Add(time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("モデレーター別の操作件数取得エラー: %w", err)
+	}
+	log.Printf("✅ モデレーター別の操作件数: %d人", len(activity))
+
+	// 後片付け（監査ログは外部キーで削除される）
+	for _, id := range ids {
+		if err := commentRepo.Delete(id); err != nil {
+			return fmt.Errorf("コメント削除エラー: %w", err)
+		}
+	}
+	log.Printf("✅ コメント削除完了")
+
+	return nil
+}
+
 // ptr 文字列のポインタを返すヘルパー関数
 func ptr(s string) *string {
 	return &s
@@ -2,13 +2,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"time"
 
 	"go-db-performance-study/internal/database"
 	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/password"
 	"go-db-performance-study/internal/testdata"
 	"go-db-performance-study/internal/testdata/scenarios"
 
@@ -17,19 +21,74 @@ import (
 
 func main() {
 	var (
-		scenario = flag.String("scenario", "small", "データセット規模 (small/medium/large/custom)")
+		scenario = flag.String("scenario", "small", "シナリオ名（small/medium/large、シナリオファイルの定義名、または custom）")
+		scenFile = flag.String("scenario-file", scenarios.DefaultFile, "シナリオ定義ファイル")
+		list     = flag.Bool("list-scenarios", false, "利用できるシナリオを一覧表示して終了")
 		users    = flag.Int("users", 1000, "生成するユーザー数（customの場合）")
 		posts    = flag.Int("posts", 5000, "生成する投稿数（customの場合）")
 		tags     = flag.Int("tags", 100, "生成するタグ数（customの場合）")
 		comments = flag.Int("comments", 10000, "生成するコメント数（customの場合）")
 		env      = flag.String("env", "development", "環境 (development/testing)")
 		clean    = flag.Bool("clean", false, "既存データを削除してから実行")
+		cleanBy  = flag.String("clean-mode", string(testdata.CleanupTruncate), "削除方法 (truncate/delete/recreate/chunked: 指定シナリオの実行分だけをID範囲ごとに削除)")
+		cleanSc  = flag.String("clean-scenario", "", "chunked で削除するシナリオ名（空の場合は -scenario と同じ）")
+		chunk    = flag.Int("clean-chunk", testdata.DefaultCleanupChunkSize, "chunked で1文あたりに削除するIDの範囲")
+		onlyCln  = flag.Bool("clean-only", false, "削除だけを行い、データは生成しない")
+		seed     = flag.Int64("seed", 0, "乱数シード（0 の場合はシナリオの値、未定義ならランダム。同じシードなら同一データを再現）")
+		replies  = flag.Float64("reply-prob", -1, "コメントが返信を受ける確率（負の値はシナリオ既定、customは0 で返信なし）")
+		depth    = flag.Int("max-depth", 0, "返信の最大深さ（0 はシナリオ既定）")
+		skew     = flag.Bool("skew", false, "多作な著者・バズった投稿・人気タグに偏った分布で生成")
+		workers  = flag.Int("workers", 1, "並行して挿入するワーカー数（シードが同じならワーカー数に関係なく同一データ）")
+		method   = flag.String("method", "insert", "書き込み方法 (insert: 複数行INSERT / load-data: LOAD DATA LOCAL INFILE)")
+		noIndex  = flag.Bool("disable-indexes", false, "各テーブルの投入中はセカンダリインデックスを削除し、完了後に再作成")
+		noChecks = flag.Bool("disable-checks", false, "投入中は unique_checks / foreign_key_checks を無効化")
+		pwHash   = flag.String("password-hash", string(password.AlgorithmFast), "ユーザーのパスワードハッシュの方式 (fast/bcrypt/argon2id。ベンチマーク用のデータなので既定は fast。パラメータは "+password.DefaultFile+")")
+		resume   = flag.Bool("resume", false, "中断した最新の実行を最後にコミットされたバッチから再開（規模・シード等は中断前の設定を使用）")
 	)
 	flag.Parse()
 
+	catalog, err := scenarios.LoadCatalog(*scenFile)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if *list {
+		printScenarios(catalog)
+		return
+	}
+
+	if *onlyCln {
+		*clean = true
+	}
+	if *resume && *clean {
+		log.Fatalf("-resume と -clean は同時に指定できません")
+	}
+	if err := testdata.CleanupMode(*cleanBy).Validate(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := testdata.InsertMethod(*method).Validate(); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	pwConfig, err := password.LoadConfig(password.DefaultFile)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	pwConfig.Algorithm = password.Algorithm(*pwHash)
+	hasher, err := password.New(pwConfig)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	password.SetDefault(hasher)
+
 	log.Printf("=== テストデータ生成ツール ===")
 	log.Printf("シナリオ: %s", *scenario)
 	log.Printf("環境: %s", *env)
+	log.Printf("ワーカー数: %d, 書き込み方法: %s", *workers, *method)
+	log.Printf("パスワードハッシュ: %s", hasher.Algorithm())
+
+	// Ctrl+C で全ワーカーを停止
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	// データベース接続
 	db, err := database.Connect(*env)
@@ -40,34 +99,74 @@ func main() {
 
 	// 既存データクリーンアップ
 	if *clean {
-		log.Println("既存データを削除中...")
-		if err := cleanupData(db); err != nil {
+		opts := testdata.CleanupOptions{Mode: testdata.CleanupMode(*cleanBy), Scenario: *cleanSc, ChunkSize: *chunk}
+		if opts.Scenario == "" {
+			opts.Scenario = *scenario
+		}
+		if err := cleanupData(db, opts); err != nil {
 			log.Fatalf("データクリーンアップエラー: %v", err)
 		}
+		if *onlyCln {
+			return
+		}
 	}
 
 	startTime := time.Now()
 
 	// シナリオ別実行
-	switch *scenario {
-	case "small":
-		err = scenarios.GenerateSmallDataset(db)
-	case "medium":
-		err = scenarios.GenerateMediumDataset(db)
-	case "large":
-		err = scenarios.GenerateLargeDataset(db)
-	case "custom":
+	if *resume {
+		generator, resumeErr := testdata.ResumeDataGenerator(db, testdata.ResumeOptions{
+			Workers:        *workers,
+			InsertMethod:   testdata.InsertMethod(*method),
+			DisableIndexes: *noIndex,
+			DisableChecks:  *noChecks,
+		})
+		if resumeErr != nil {
+			log.Fatalf("再開エラー: %v", resumeErr)
+		}
+		err = generator.WithContext(ctx).Resume()
+	} else if *scenario == "custom" {
 		config := testdata.GeneratorConfig{
 			UserCount:    *users,
 			PostCount:    *posts,
 			TagCount:     *tags,
 			CommentCount: *comments,
 			BatchSize:    1000,
+			Seed:         *seed,
+			Scenario:     *scenario,
+
+			Workers:        *workers,
+			InsertMethod:   testdata.InsertMethod(*method),
+			DisableIndexes: *noIndex,
+			DisableChecks:  *noChecks,
+		}
+		applyThreadFlags(&config, *replies, *depth)
+		if *skew {
+			config.Distributions = testdata.SkewedDistributions()
 		}
-		generator := testdata.NewDataGenerator(db, config)
+		generator := testdata.NewDataGenerator(db, config).WithContext(ctx)
 		err = generator.GenerateAllSafe() // ← UTF-8安全版メソッド
-	default:
-		log.Fatalf("未知のシナリオ: %s", *scenario)
+	} else {
+		selected, cfgErr := catalog.Get(*scenario)
+		if cfgErr != nil {
+			log.Fatalf("%v（-list-scenarios で一覧を確認できます）", cfgErr)
+		}
+		log.Printf("シナリオ定義: %s (%s)", selected.Name, selected.Source)
+		config := selected.Config
+		config.Scenario = selected.Name
+		if *seed != 0 {
+			config.Seed = *seed
+		}
+		config.Workers = *workers
+		config.InsertMethod = testdata.InsertMethod(*method)
+		config.DisableIndexes = *noIndex
+		config.DisableChecks = *noChecks
+		applyThreadFlags(&config, *replies, *depth)
+		if *skew {
+			config.Distributions = testdata.SkewedDistributions()
+		}
+		generator := testdata.NewDataGenerator(db, config).WithContext(ctx)
+		err = generator.GenerateAll()
 	}
 
 	if err != nil {
@@ -83,20 +182,48 @@ func main() {
 	}
 }
 
-// cleanupData 既存データを削除
-func cleanupData(db *gorm.DB) error {
-	tables := []string{"comments", "post_tags", "posts", "tags", "users"}
+// printScenarios シナリオの一覧を表示
+func printScenarios(catalog *scenarios.Catalog) {
+	fmt.Printf("%-20s %10s %10s %8s %12s  %s\n", "名前", "ユーザー", "投稿", "タグ", "コメント", "説明")
+	for _, s := range catalog.List() {
+		c := s.Config
+		fmt.Printf("%-20s %10d %10d %8d %12d  %s [%s]\n",
+			s.Name, c.UserCount, c.PostCount, c.TagCount, c.CommentCount, s.Description, s.Source)
+	}
+}
 
-	for _, table := range tables {
-		if err := db.Exec(fmt.Sprintf("DELETE FROM %s", table)).Error; err != nil {
-			return fmt.Errorf("テーブル %s のクリーンアップエラー: %w", table, err)
-		}
-		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = 1", table)).Error; err != nil {
-			log.Printf("AUTO_INCREMENT リセット警告 (%s): %v", table, err)
-		}
+// applyThreadFlags 返信ツリー関連のフラグで設定を上書き
+func applyThreadFlags(config *testdata.GeneratorConfig, replyProb float64, maxDepth int) {
+	if replyProb >= 0 {
+		config.ReplyProbability = replyProb
 	}
+	if maxDepth > 0 {
+		config.MaxReplyDepth = maxDepth
+	}
+}
 
-	log.Println("既存データの削除が完了しました")
+// cleanupData 既存データを削除し、テーブルごとの所要時間を表示
+func cleanupData(db *gorm.DB, opts testdata.CleanupOptions) error {
+	if opts.Mode == testdata.CleanupChunked {
+		log.Printf("シナリオ %s の生成データを削除中（%s）...", opts.Scenario, opts.Mode)
+	} else {
+		log.Printf("既存データを削除中（%s）...", opts.Mode)
+	}
+
+	start := time.Now()
+	stats, err := testdata.Cleanup(db, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		rows := "-"
+		if s.Rows >= 0 {
+			rows = fmt.Sprintf("%d件", s.Rows)
+		}
+		log.Printf("  %-20s %12s %12v", s.Table, rows, s.Elapsed.Round(time.Millisecond))
+	}
+	log.Printf("既存データの削除が完了しました (%v)", time.Since(start).Round(time.Millisecond))
 	return nil
 }
 
@@ -0,0 +1,172 @@
+// cmd/maintenance/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/maintenance"
+	"go-db-performance-study/internal/models"
+	gorm_repo "go-db-performance-study/internal/repository/gorm"
+	"go-db-performance-study/internal/textutil"
+)
+
+// subcommand メンテナンス作業
+type subcommand struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var subcommands = []subcommand{
+	{"prune-slugs", "不要になった slug 履歴（削除済みの行・現在の slug と重複・古い履歴）を削除する", runPruneSlugs},
+	{"rollup-comments", "ユーザー別・日別のコメント集計（コメント活動統計の事前集計）を作り直す", runRollupComments},
+	{"backfill-comment-paths", "コメントの path（スレッドのマテリアライズドパス）をルートコメントから作り直す", runBackfillCommentPaths},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, sc := range subcommands {
+		if sc.name == name {
+			if err := sc.run(os.Args[2:]); err != nil {
+				log.Fatalf("%s エラー: %v", name, err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "未知のサブコマンド: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage 使い方を表示
+func usage() {
+	fmt.Fprintf(os.Stderr, "使い方: maintenance <サブコマンド> [フラグ]\n\nサブコマンド:\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", sc.name, sc.description)
+	}
+	fmt.Fprintf(os.Stderr, "\n各サブコマンドのフラグは maintenance <サブコマンド> -h で確認できます\n")
+}
+
+// runPruneSlugs slug 履歴を削除する
+func runPruneSlugs(args []string) error {
+	fs := flag.NewFlagSet("prune-slugs", flag.ExitOnError)
+	var (
+		env       = fs.String("env", "development", "環境 (development/testing)")
+		olderThan = fs.Duration("older-than", 0, "これより前に記録した履歴を削除（例: 8760h。0 は期間で削除しない）")
+		keep      = fs.Int("keep", 0, "投稿・タグごとに新しい順で残す件数（0 は件数で削除しない）")
+		dryRun    = fs.Bool("dry-run", false, "削除せずに対象の件数だけを表示")
+	)
+	fs.Parse(args)
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	mode := "削除"
+	if *dryRun {
+		mode = "確認のみ"
+	}
+	log.Printf("=== slug 履歴の整理 (%s, %s) ===", *env, mode)
+	start := time.Now()
+	stats, err := maintenance.PruneSlugHistory(db, maintenance.SlugHistoryOptions{
+		OlderThan: *olderThan,
+		Keep:      *keep,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, s := range stats {
+		fmt.Printf("  %s %8d件 (%v)\n", textutil.PadRight(s.Rule, 28), s.Rows, s.Elapsed.Round(time.Millisecond))
+		total += s.Rows
+	}
+	if *dryRun {
+		log.Printf("✅ 削除対象: 延べ %d件（複数の条件に一致する行は重複して数える） (%v)", total, time.Since(start).Round(time.Millisecond))
+		return nil
+	}
+	log.Printf("✅ %d件を削除しました (%v)", total, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// runRollupComments コメントの日別集計を作り直す
+func runRollupComments(args []string) error {
+	fs := flag.NewFlagSet("rollup-comments", flag.ExitOnError)
+	var (
+		env  = fs.String("env", "development", "環境 (development/testing)")
+		from = fs.String("from", "", "作り直す最初の日（例: 2024-01-01。空の場合は最初のコメントの日）")
+		to   = fs.String("to", "", "作り直す最後の日の翌日（例: 2024-02-01。空の場合は最後のコメントの日まで）")
+	)
+	fs.Parse(args)
+
+	var fromDay, toDay time.Time
+	for _, d := range []struct {
+		value string
+		dest  *time.Time
+	}{{*from, &fromDay}, {*to, &toDay}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", d.value, time.Local)
+		if err != nil {
+			return fmt.Errorf("日付の形式が不正です（2006-01-02 の形式で指定）: %s", d.value)
+		}
+		*d.dest = t
+	}
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if !db.Migrator().HasTable(&models.CommentDailyRollup{}) {
+		return fmt.Errorf("テーブル comment_daily_rollups が存在しません（マイグレーションを実行してください）")
+	}
+
+	log.Printf("=== コメントの日別集計 (%s) ===", *env)
+	start := time.Now()
+	days, rows, err := gorm_repo.NewCommentRepository(db).RefreshActivityRollup(fromDay, toDay)
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ %d日分・%d行を作り直しました (%v)", days, rows, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// runBackfillCommentPaths コメントの path を作り直す
+func runBackfillCommentPaths(args []string) error {
+	fs := flag.NewFlagSet("backfill-comment-paths", flag.ExitOnError)
+	var (
+		env       = fs.String("env", "development", "環境 (development/testing)")
+		chunkSize = fs.Int("chunk-size", 1000, "1回の UPDATE で対象にするルートコメントの ID の範囲")
+	)
+	fs.Parse(args)
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	log.Printf("=== コメントの path の作り直し (%s) ===", *env)
+	stat, err := maintenance.BackfillCommentPaths(db, *chunkSize)
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ %d件の path を更新しました（UPDATE %d回） (%v)", stat.Rows, stat.Chunks, stat.Elapsed.Round(time.Millisecond))
+	return nil
+}
@@ -55,7 +55,7 @@ func createTestUser(db *gorm.DB) error {
 	user := &models.User{
 		Name:     "テストユーザー",
 		Email:    "test@example.com",
-		Password: "password123", // 本来はハッシュ化が必要
+		Password: "password123", // BeforeCreate で configs/password.yaml の方式でハッシュ化される
 	}
 
 	// 既存ユーザーがいるかチェック
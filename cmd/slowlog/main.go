@@ -0,0 +1,118 @@
+// cmd/slowlog/main.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/slowlog"
+	"go-db-performance-study/internal/textutil"
+)
+
+func main() {
+	var (
+		file     = flag.String("file", "slow.log", "スロークエリログのパス（- で標準入力）")
+		top      = flag.Int("top", 20, "表示する指紋の件数（0 で全件）")
+		sortKey  = flag.String("sort", "total", "並び順 (total/count/avg/p95/examined)")
+		format   = flag.String("format", "text", "出力形式 (text/json)")
+		minCount = flag.Int("min-count", 1, "表示する最小実行回数")
+	)
+	flag.Parse()
+
+	switch slowlog.SortKey(*sortKey) {
+	case slowlog.SortByTotal, slowlog.SortByCount, slowlog.SortByAvg, slowlog.SortByP95, slowlog.SortByExamined:
+	default:
+		log.Fatalf("未知の並び順: %s", *sortKey)
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("スローログを開けません: %v", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	parser := slowlog.NewParser(input)
+	aggregator := slowlog.NewAggregator()
+	entries := 0
+
+	for {
+		entry, err := parser.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("スローログ解析エラー: %v", err)
+		}
+		aggregator.Add(entry)
+		entries++
+	}
+
+	results := aggregator.Results(slowlog.SortKey(*sortKey))
+	filtered := results[:0]
+	for _, r := range results {
+		if r.Count >= *minCount {
+			filtered = append(filtered, r)
+		}
+	}
+	if *top > 0 && len(filtered) > *top {
+		filtered = filtered[:*top]
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(filtered); err != nil {
+			log.Fatalf("JSON出力エラー: %v", err)
+		}
+	case "text":
+		printText(filtered, entries, len(results))
+	default:
+		log.Fatalf("未知の出力形式: %s", *format)
+	}
+}
+
+// printText テキスト形式でレポートを出力
+func printText(results []slowlog.QueryStats, entries, fingerprints int) {
+	fmt.Printf("=== スロークエリレポート ===\n")
+	fmt.Printf("エントリ数: %d件 / 指紋数: %d件\n\n", entries, fingerprints)
+
+	for i, r := range results {
+		fmt.Printf("#%d  実行回数=%d  合計=%s  平均=%s  p95=%s  最大=%s\n",
+			i+1, r.Count, formatDuration(r.TotalTime), formatDuration(r.AvgTime),
+			formatDuration(r.P95Time), formatDuration(r.MaxTime))
+		fmt.Printf("    走査行数=%d  送信行数=%d  走査/送信=%.1f  ロック合計=%s\n",
+			r.RowsExamined, r.RowsSent, r.ExaminedPerSent, formatDuration(r.TotalLockTime))
+		if !r.FirstSeen.IsZero() {
+			fmt.Printf("    期間: %s 〜 %s\n",
+				r.FirstSeen.Format(time.RFC3339), r.LastSeen.Format(time.RFC3339))
+		}
+		if len(r.Origins) > 0 {
+			fmt.Printf("    発行元: %s\n", strings.Join(r.Origins, ", "))
+		} else {
+			fmt.Printf("    発行元: (不明)\n")
+		}
+		fmt.Printf("    指紋: %s\n", r.Fingerprint)
+		fmt.Printf("    実例: %s\n\n", truncate(r.Example, 300))
+	}
+}
+
+// formatDuration 秒単位で表示
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+// truncate 長いクエリを1行にまとめて省略表示
+func truncate(s string, n int) string {
+	return textutil.Truncate(strings.Join(strings.Fields(s), " "), n, textutil.Ellipsis)
+}
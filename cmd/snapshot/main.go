@@ -0,0 +1,159 @@
+// cmd/snapshot/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/snapshot"
+)
+
+// defaultDir スナップショットの既定の保存先
+const defaultDir = "snapshots/latest"
+
+// subcommand スナップショットの操作
+type subcommand struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var subcommands = []subcommand{
+	{"export", "全テーブルをスナップショット（テーブルごとの gzip 圧縮 CSV とマニフェスト）に書き出す", runExport},
+	{"import", "スナップショットを LOAD DATA で読み込み、チェックサムを照合する", runImport},
+	{"info", "スナップショットのマニフェストを表示する", runInfo},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, sc := range subcommands {
+		if sc.name == name {
+			if err := sc.run(os.Args[2:]); err != nil {
+				log.Fatalf("%s エラー: %v", name, err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "未知のサブコマンド: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage 使い方を表示
+func usage() {
+	fmt.Fprintf(os.Stderr, "使い方: snapshot <サブコマンド> [フラグ]\n\nサブコマンド:\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", sc.name, sc.description)
+	}
+	fmt.Fprintf(os.Stderr, "\n各サブコマンドのフラグは snapshot <サブコマンド> -h で確認できます\n")
+}
+
+// runExport データベースの内容をスナップショットに書き出す
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var (
+		env   = fs.String("env", "development", "環境 (development/testing)")
+		dir   = fs.String("dir", defaultDir, "書き出し先のディレクトリ")
+		force = fs.Bool("force", false, "既存のスナップショットを上書き")
+		level = fs.Int("level", 0, "gzip の圧縮レベル (1-9、0 は既定値)")
+	)
+	fs.Parse(args)
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	log.Printf("=== スナップショットの書き出し: %s → %s ===", *env, *dir)
+	start := time.Now()
+	manifest, err := snapshot.Export(db, *dir, snapshot.ExportOptions{Force: *force, Level: *level})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✅ %d行を書き出しました (%v)", manifest.TotalRows(), time.Since(start).Round(time.Millisecond))
+	log.Printf("チェックサム: %s", manifest.Dataset.Checksum)
+	return nil
+}
+
+// runImport スナップショットをデータベースに読み込む
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var (
+		env      = fs.String("env", "development", "環境 (development/testing)")
+		dir      = fs.String("dir", defaultDir, "読み込むスナップショットのディレクトリ")
+		replace  = fs.Bool("replace", false, "既存データを削除してから読み込む")
+		noIndex  = fs.Bool("disable-indexes", false, "読み込み中はセカンダリインデックスを削除し、最後に作り直す")
+		noVerify = fs.Bool("no-verify", false, "読み込み後のチェックサム照合を省略")
+	)
+	fs.Parse(args)
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+
+	log.Printf("=== スナップショットの読み込み: %s → %s ===", *dir, *env)
+	start := time.Now()
+	manifest, err := snapshot.Import(db, *dir, snapshot.ImportOptions{
+		Replace:        *replace,
+		DisableIndexes: *noIndex,
+		SkipVerify:     *noVerify,
+	})
+	if err != nil {
+		return err
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("✅ %d行を読み込みました (%v, %.0f行/秒)",
+		manifest.TotalRows(), elapsed.Round(time.Millisecond), float64(manifest.TotalRows())/elapsed.Seconds())
+	if !*noVerify && manifest.Dataset != nil {
+		log.Printf("チェックサム一致: %s", manifest.Dataset.Checksum)
+	}
+	return nil
+}
+
+// runInfo マニフェストの内容を表示
+func runInfo(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	dir := fs.String("dir", defaultDir, "スナップショットのディレクトリ")
+	fs.Parse(args)
+
+	manifest, err := snapshot.ReadManifest(*dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("形式:       version %d (%s)\n", manifest.Version, manifest.Format)
+	fmt.Printf("作成日時:   %s\n", manifest.CreatedAt.Format(time.RFC3339))
+	fmt.Printf("元データベース: %s\n", manifest.Database)
+	if g := manifest.Generator; g != nil {
+		fmt.Printf("生成設定:   scenario=%s seed=%d base_time=%s\n", g.Scenario, g.Seed, g.BaseTime.Format(time.RFC3339))
+	}
+	fmt.Printf("\n%-10s %12s %10s  %s\n", "テーブル", "行数", "サイズ(MB)", "SHA-256")
+	var bytes int64
+	for _, t := range manifest.Tables {
+		fmt.Printf("%-10s %12d %10.1f  %s\n", t.Table, t.Rows, float64(t.Bytes)/1024/1024, t.SHA256[:16])
+		bytes += t.Bytes
+	}
+	fmt.Printf("%-10s %12d %10.1f\n", "合計", manifest.TotalRows(), float64(bytes)/1024/1024)
+	if manifest.Dataset != nil {
+		fmt.Printf("\nデータセットチェックサム: %s\n", manifest.Dataset.Checksum)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// cmd/verify/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/database"
+	"go-db-performance-study/internal/integrity"
+)
+
+func main() {
+	var (
+		env     = flag.String("env", "development", "環境 (development/testing)")
+		fix     = flag.Bool("fix", false, "見つかった問題を修正する（修正できないチェックは報告のみ）")
+		only    = flag.String("checks", "", "実行するチェック（カンマ区切り。空の場合は全て）")
+		samples = flag.Int("samples", 5, "チェックごとに表示する問題の例の件数")
+		list    = flag.Bool("list", false, "チェックの一覧を表示して終了")
+	)
+	flag.Parse()
+
+	if *list {
+		printChecks()
+		return
+	}
+
+	db, err := database.Connect(*env)
+	if err != nil {
+		log.Fatalf("データベース接続エラー: %v", err)
+	}
+	defer database.Close()
+
+	opts := integrity.Options{Fix: *fix, Samples: *samples}
+	if *only != "" {
+		opts.Only = strings.Split(*only, ",")
+	}
+
+	log.Printf("=== データ整合性チェック (%s) ===", *env)
+	start := time.Now()
+	results, err := integrity.Run(db, opts)
+	if err != nil {
+		log.Fatalf("整合性チェックエラー: %v", err)
+	}
+
+	failed := printReport(results)
+	log.Printf("完了 (%v)", time.Since(start).Round(time.Millisecond))
+	if failed > 0 {
+		log.Printf("❌ %d 件のチェックで問題が残っています", failed)
+		os.Exit(1)
+	}
+	log.Printf("✅ 問題は見つかりませんでした")
+}
+
+// printReport チェック結果を表示し、問題が残っているチェックの数を返す
+func printReport(results []integrity.Result) int {
+	failed := 0
+	fmt.Println()
+	for _, r := range results {
+		mark := "✅"
+		if r.Count > 0 {
+			mark = "❌"
+			if r.OK() {
+				mark = "🔧"
+			}
+		}
+		fmt.Printf("%s %-24s %8d件  %s (%v)\n", mark, r.Check.Name, r.Count, r.Check.Description, r.Elapsed.Round(time.Millisecond))
+		if r.Count == 0 {
+			continue
+		}
+
+		for _, s := range r.Samples {
+			fmt.Printf("     - %s\n", s)
+		}
+		if int64(len(r.Samples)) < r.Count {
+			fmt.Printf("     ...ほか %d件\n", r.Count-int64(len(r.Samples)))
+		}
+
+		switch {
+		case r.Fixed:
+			fmt.Printf("     修正: %s（残り %d件）\n", r.Check.FixNote, r.Remaining)
+		case r.Check.Fixable():
+			fmt.Printf("     -fix で修正できます: %s\n", r.Check.FixNote)
+		default:
+			fmt.Printf("     自動修正には対応していません\n")
+		}
+		if !r.OK() {
+			failed++
+		}
+	}
+	fmt.Println()
+	return failed
+}
+
+// printChecks チェックの一覧を表示
+func printChecks() {
+	for _, c := range integrity.Checks() {
+		fix := c.FixNote
+		if !c.Fixable() {
+			fix = "なし"
+		}
+		fmt.Printf("%-24s %s\n%-24s   修正: %s\n", c.Name, c.Description, "", fix)
+	}
+}
@@ -0,0 +1,157 @@
+// internal/account/service.go
+package account
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/repository/interfaces"
+)
+
+// ErrInvalidToken トークンが存在しない・使用済み・期限切れ
+//
+// どれに当たるかは利用者に区別させない（存在しないトークンを探る手がかりにしない）。
+var ErrInvalidToken = errors.New("トークンが無効または期限切れです")
+
+// ErrAlreadyVerified メール認証済みのユーザーに認証トークンを発行しようとした
+var ErrAlreadyVerified = errors.New("メールアドレスは認証済みです")
+
+// Config トークンの有効期間
+type Config struct {
+	VerificationTTL time.Duration // メール認証トークン
+	RememberTTL     time.Duration // ログイン保持トークン（使うたびに新しいトークンで延長する）
+}
+
+// DefaultConfig デフォルトの有効期間
+func DefaultConfig() Config {
+	return Config{
+		VerificationTTL: 24 * time.Hour,
+		RememberTTL:     30 * 24 * time.Hour,
+	}
+}
+
+// Service メール認証とログイン保持（remember me）のトークンを扱う
+//
+// トークンの平文は発行時に返すだけで、DB には HashToken のハッシュを保存する。
+type Service struct {
+	users  interfaces.UserRepository
+	config Config
+	now    func() time.Time
+}
+
+// NewService サービスを作成
+func NewService(users interfaces.UserRepository, config Config) (*Service, error) {
+	if config.VerificationTTL <= 0 || config.RememberTTL <= 0 {
+		return nil, fmt.Errorf("トークンの有効期間は正の値を指定してください: verification=%v remember=%v",
+			config.VerificationTTL, config.RememberTTL)
+	}
+	return &Service{users: users, config: config, now: time.Now}, nil
+}
+
+// IssueVerificationToken メール認証トークンを発行する（前に発行したトークンは無効になる）
+func (s *Service) IssueVerificationToken(userID uint) (string, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if user.IsEmailVerified() {
+		return "", ErrAlreadyVerified
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.users.SetVerificationToken(userID, hash, s.now().Add(s.config.VerificationTTL)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// VerifyEmail メール認証トークンを使って EmailVerifiedAt を設定する（トークンは1回だけ使える）
+func (s *Service) VerifyEmail(token string) (*models.User, error) {
+	hash := HashToken(token)
+	user, err := s.users.GetByVerificationToken(hash)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if user == nil || expired(user.EmailVerificationExpiresAt, now) {
+		return nil, ErrInvalidToken
+	}
+
+	ok, err := s.users.MarkEmailVerified(user.ID, hash, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidToken // 同時に使われた
+	}
+	user.EmailVerifiedAt = &now
+	user.EmailVerificationToken = nil
+	user.EmailVerificationExpiresAt = nil
+	return user, nil
+}
+
+// IssueRememberToken ログイン保持トークンを発行する（前に発行したトークンは無効になる）
+func (s *Service) IssueRememberToken(userID uint) (string, error) {
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	expiresAt := s.now().Add(s.config.RememberTTL)
+	if err := s.users.SetRememberToken(userID, &hash, &expiresAt); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConsumeRememberToken ログイン保持トークンでユーザーを認証し、新しいトークンに置き換える
+//
+// 使ったトークンは無効になり、戻り値の新しいトークンを次回に使う（盗まれたトークンの再利用を防ぐ）。
+// 期限切れのトークンは消す。
+func (s *Service) ConsumeRememberToken(token string) (*models.User, string, error) {
+	hash := HashToken(token)
+	user, err := s.users.GetByRememberToken(hash)
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil {
+		return nil, "", ErrInvalidToken
+	}
+	now := s.now()
+	if expired(user.RememberTokenExpiresAt, now) {
+		if err := s.users.SetRememberToken(user.ID, nil, nil); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrInvalidToken
+	}
+
+	next, nextHash, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	expiresAt := now.Add(s.config.RememberTTL)
+	ok, err := s.users.RotateRememberToken(user.ID, hash, nextHash, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", ErrInvalidToken // 同時に使われた
+	}
+	user.RememberToken = &nextHash
+	user.RememberTokenExpiresAt = &expiresAt
+	return user, next, nil
+}
+
+// ForgetRememberToken ログイン保持トークンを消す（ログアウト）
+func (s *Service) ForgetRememberToken(userID uint) error {
+	return s.users.SetRememberToken(userID, nil, nil)
+}
+
+// expired 有効期限を過ぎているか（期限のないトークンは無効として扱う）
+func expired(expiresAt *time.Time, now time.Time) bool {
+	return expiresAt == nil || !now.Before(*expiresAt)
+}
@@ -0,0 +1,32 @@
+// internal/account/token.go
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// tokenBytes トークンの乱数のバイト数（256 ビット）
+const tokenBytes = 32
+
+// newToken 暗号学的な乱数からトークンを作り、平文（利用者に渡す）とハッシュ（保存する）を返す
+func newToken() (token, hash string, err error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("トークンの生成エラー: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken トークンの保存・検索用のハッシュ（SHA-256 の16進数）
+//
+// トークンは十分に長い乱数なので、パスワードのような遅いハッシュは使わない。
+// 検索は等価比較になり、インデックスで引ける。
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,114 @@
+// internal/bulkload/csv.go
+package bulkload
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LOAD DATA に渡す CSV の書式（Writer の出力と対応させる）
+const csvFormat = `FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' ESCAPED BY '\\' LINES TERMINATED BY '\n'`
+
+// csvEscaper 囲み文字内でエスケープが必要な文字
+var csvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\x00", `\0`)
+
+// Writer LOAD DATA 用の CSV を書き出す
+//
+// NULL は \N、文字列は二重引用符で囲み、日時は接続のタイムゾーンで出力する。
+type Writer struct {
+	w   *bufio.Writer
+	loc *time.Location
+}
+
+// NewWriter CSV ライターを作成（loc が nil の場合は UTC）
+func NewWriter(w io.Writer, loc *time.Location) *Writer {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &Writer{w: bufio.NewWriterSize(w, 64*1024), loc: loc}
+}
+
+// WriteRow 1行分の値を書き出す
+func (w *Writer) WriteRow(values []interface{}) error {
+	for i, v := range values {
+		if i > 0 {
+			w.w.WriteByte(',')
+		}
+		if err := w.writeValue(v); err != nil {
+			return err
+		}
+	}
+	return w.w.WriteByte('\n')
+}
+
+// Flush バッファを書き出す
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// writeValue 値を1つ書き出す
+func (w *Writer) writeValue(v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		w.w.WriteString(`\N`)
+	case string:
+		w.writeQuoted(val)
+	case []byte:
+		w.writeQuoted(string(val))
+	case bool:
+		if val {
+			w.w.WriteByte('1')
+		} else {
+			w.w.WriteByte('0')
+		}
+	case int:
+		w.w.WriteString(strconv.FormatInt(int64(val), 10))
+	case int64:
+		w.w.WriteString(strconv.FormatInt(val, 10))
+	case uint:
+		w.w.WriteString(strconv.FormatUint(uint64(val), 10))
+	case uint64:
+		w.w.WriteString(strconv.FormatUint(val, 10))
+	case float64:
+		w.w.WriteString(strconv.FormatFloat(val, 'f', -1, 64))
+	case time.Time:
+		w.w.WriteString(val.In(w.loc).Format("2006-01-02 15:04:05.999999"))
+	default:
+		return w.writeReflect(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+// writeReflect ポインタや独自型（models.PostStatus など）を基底の型に直して書き出す
+func (w *Writer) writeReflect(rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return w.writeValue(nil)
+		}
+		return w.writeValue(rv.Elem().Interface())
+	case reflect.String:
+		return w.writeValue(rv.String())
+	case reflect.Bool:
+		return w.writeValue(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return w.writeValue(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return w.writeValue(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return w.writeValue(rv.Float())
+	}
+	return fmt.Errorf("CSV に変換できない型です: %s", rv.Type())
+}
+
+// writeQuoted 文字列を囲み文字付きで書き出す
+func (w *Writer) writeQuoted(s string) {
+	w.w.WriteByte('"')
+	csvEscaper.WriteString(w.w, s)
+	w.w.WriteByte('"')
+}
@@ -0,0 +1,142 @@
+// internal/bulkload/indexes.go
+package bulkload
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SetChecks セッションの unique_checks / foreign_key_checks を切り替える
+//
+// 接続単位の設定のため、同じ接続（トランザクション）で続けて挿入するときに使う。
+func SetChecks(tx *gorm.DB, enabled bool) error {
+	v := 0
+	if enabled {
+		v = 1
+	}
+	if err := tx.Exec(fmt.Sprintf("SET SESSION unique_checks = %d, foreign_key_checks = %d", v, v)).Error; err != nil {
+		return fmt.Errorf("チェック設定の変更エラー: %w", err)
+	}
+	return nil
+}
+
+// WithoutChecks unique_checks / foreign_key_checks を無効にして fn を実行し、終了後に元に戻す
+func WithoutChecks(tx *gorm.DB, fn func(tx *gorm.DB) error) (err error) {
+	if err := SetChecks(tx, false); err != nil {
+		return err
+	}
+	defer func() {
+		if restoreErr := SetChecks(tx, true); restoreErr != nil && err == nil {
+			err = restoreErr
+		}
+	}()
+	return fn(tx)
+}
+
+// secondaryIndex 削除して後から作り直すセカンダリインデックス
+type secondaryIndex struct {
+	Name    string
+	Columns []string // プレフィックス長付きの列定義
+}
+
+// definition ADD INDEX 句
+func (i secondaryIndex) definition() string {
+	return fmt.Sprintf("ADD INDEX `%s` (%s)", i.Name, strings.Join(i.Columns, ", "))
+}
+
+// DisableIndexes 一意でないセカンダリインデックスを削除し、再作成する関数を返す
+//
+// InnoDB では DISABLE KEYS が効かないため、インデックスを削除してから一括投入し、
+// 最後にまとめて作り直す。主キー・一意インデックス・外部キーが使うインデックスは残す。
+// 再作成前に異常終了した場合に備え、再作成用の DDL をログに出力する。
+func DisableIndexes(db *gorm.DB, table string) (restore func() error, err error) {
+	indexes, err := droppableIndexes(db, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) == 0 {
+		return func() error { return nil }, nil
+	}
+
+	drops := make([]string, len(indexes))
+	adds := make([]string, len(indexes))
+	names := make([]string, len(indexes))
+	for i, idx := range indexes {
+		drops[i] = fmt.Sprintf("DROP INDEX `%s`", idx.Name)
+		adds[i] = idx.definition()
+		names[i] = idx.Name
+	}
+	recreate := fmt.Sprintf("ALTER TABLE `%s` %s", table, strings.Join(adds, ", "))
+
+	log.Printf("%s: インデックスを一時的に削除します (%s)", table, strings.Join(names, ", "))
+	log.Printf("%s: 中断した場合は次の SQL で再作成してください: %s", table, recreate)
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` %s", table, strings.Join(drops, ", "))).Error; err != nil {
+		return nil, fmt.Errorf("%s のインデックス削除エラー: %w", table, err)
+	}
+
+	return func() error {
+		log.Printf("%s: インデックスを再作成中...", table)
+		if err := db.Exec(recreate).Error; err != nil {
+			return fmt.Errorf("%s のインデックス再作成エラー: %w", table, err)
+		}
+		return nil
+	}, nil
+}
+
+// droppableIndexes 削除してよいセカンダリインデックスを取得
+func droppableIndexes(db *gorm.DB, table string) ([]secondaryIndex, error) {
+	var stats []struct {
+		IndexName  string
+		ColumnName string
+		NonUnique  int
+		SubPart    *int
+	}
+	err := db.Raw(`SELECT INDEX_NAME AS index_name, COLUMN_NAME AS column_name, NON_UNIQUE AS non_unique, SUB_PART AS sub_part
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`, table).Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("%s のインデックス取得エラー: %w", table, err)
+	}
+
+	// 外部キー列を先頭に持つインデックスは制約が使うため削除できない
+	var fkColumns []string
+	err = db.Raw(`SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL`, table).
+		Scan(&fkColumns).Error
+	if err != nil {
+		return nil, fmt.Errorf("%s の外部キー取得エラー: %w", table, err)
+	}
+	isFK := make(map[string]bool, len(fkColumns))
+	for _, c := range fkColumns {
+		isFK[c] = true
+	}
+
+	var indexes []secondaryIndex
+	byName := map[string]int{}
+	skip := map[string]bool{}
+	for _, s := range stats {
+		if s.IndexName == "PRIMARY" || s.NonUnique == 0 || skip[s.IndexName] {
+			continue
+		}
+		i, ok := byName[s.IndexName]
+		if !ok {
+			if isFK[s.ColumnName] {
+				skip[s.IndexName] = true
+				continue
+			}
+			indexes = append(indexes, secondaryIndex{Name: s.IndexName})
+			i = len(indexes) - 1
+			byName[s.IndexName] = i
+		}
+		column := "`" + s.ColumnName + "`"
+		if s.SubPart != nil {
+			column = fmt.Sprintf("%s(%d)", column, *s.SubPart)
+		}
+		indexes[i].Columns = append(indexes[i].Columns, column)
+	}
+	return indexes, nil
+}
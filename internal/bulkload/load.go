@@ -0,0 +1,168 @@
+// internal/bulkload/load.go
+package bulkload
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/schema"
+)
+
+// ErrRowsSkipped LOAD DATA で重複キーなどにより読み飛ばされた行がある
+var ErrRowsSkipped = errors.New("読み飛ばされた行があります")
+
+// handlerSeq リーダーハンドラー名の連番（並行実行時の名前の衝突を防ぐ）
+var handlerSeq uint64
+
+// LoadReader CSV を LOAD DATA LOCAL INFILE で読み込み、挿入した行数を返す
+//
+// r の内容は go-sql-driver の RegisterReaderHandler 経由でそのままサーバーへ送られるため、
+// 一時ファイルは作らない。サーバー側で local_infile が有効になっている必要がある。
+func LoadReader(tx *gorm.DB, table string, columns []string, r io.Reader) (int64, error) {
+	name := fmt.Sprintf("bulkload-%s-%d", table, atomic.AddUint64(&handlerSeq, 1))
+	mysql.RegisterReaderHandler(name, func() io.Reader { return r })
+	defer mysql.DeregisterReaderHandler(name)
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = "`" + c + "`"
+	}
+	query := fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE `%s` CHARACTER SET utf8mb4 %s (%s)",
+		name, table, csvFormat, strings.Join(quoted, ", "))
+
+	result := tx.Exec(query)
+	if result.Error != nil {
+		return 0, fmt.Errorf("%s への LOAD DATA エラー: %w", table, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Load モデルのスライスを LOAD DATA LOCAL INFILE で挿入
+//
+// CreateInBatches と同じ結果になるよう、BeforeCreate フックの実行（Session の SkipHooks を指定した場合は実行しない）・
+// 作成日時の自動設定・ゼロ値へのデフォルト値の適用を行ってから CSV に変換する。
+// LOCAL 指定の LOAD DATA は重複キーなどを警告扱いで読み飛ばすため、件数が合わなければエラーにする。
+func Load(tx *gorm.DB, rows interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(rows))
+	if rv.Kind() != reflect.Slice {
+		return fmt.Errorf("Load にはスライスを渡してください: %T", rows)
+	}
+	if rv.Len() == 0 {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(rows); err != nil {
+		return fmt.Errorf("モデル解析エラー: %w", err)
+	}
+	sch := stmt.Schema
+	fields := loadFields(sch)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.DBName
+	}
+
+	// フックはここで同期的に実行し、エラーは書き込み前に返す
+	now := time.Now()
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		if err := beforeCreate(tx, sch, elem); err != nil {
+			return err
+		}
+		setAutoTimes(tx, fields, elem, now)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		w := NewWriter(pw, ConnLocation(tx))
+		values := make([]interface{}, len(fields))
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i)
+			for j, f := range fields {
+				values[j] = fieldValue(tx, f, elem)
+			}
+			if err := w.WriteRow(values); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(w.Flush())
+	}()
+
+	loaded, err := LoadReader(tx, sch.Table, columns, pr)
+	pr.Close()
+	if err != nil {
+		return err
+	}
+	if loaded != int64(rv.Len()) {
+		return fmt.Errorf("%s: LOAD DATA で挿入されたのは %d/%d 行です（重複キーや型変換の警告を確認してください）: %w",
+			sch.Table, loaded, rv.Len(), ErrRowsSkipped)
+	}
+	return nil
+}
+
+// loadFields 挿入対象の列（リレーションや読み取り専用の列を除く）
+func loadFields(sch *schema.Schema) []*schema.Field {
+	fields := make([]*schema.Field, 0, len(sch.DBNames))
+	for _, name := range sch.DBNames {
+		if f := sch.LookUpField(name); f != nil && f.Creatable {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// beforeCreate BeforeCreate フックを実行
+func beforeCreate(tx *gorm.DB, sch *schema.Schema, elem reflect.Value) error {
+	if !sch.BeforeCreate || tx.Statement.SkipHooks {
+		return nil
+	}
+	if elem.Kind() != reflect.Ptr {
+		elem = elem.Addr()
+	}
+	if hook, ok := elem.Interface().(callbacks.BeforeCreateInterface); ok {
+		return hook.BeforeCreate(tx)
+	}
+	return nil
+}
+
+// setAutoTimes autoCreateTime / autoUpdateTime の列が未設定なら現在時刻を入れる
+func setAutoTimes(tx *gorm.DB, fields []*schema.Field, elem reflect.Value, now time.Time) {
+	for _, f := range fields {
+		if f.AutoCreateTime == 0 && f.AutoUpdateTime == 0 {
+			continue
+		}
+		if _, isZero := f.ValueOf(tx.Statement.Context, elem); isZero {
+			f.Set(tx.Statement.Context, elem, now)
+		}
+	}
+}
+
+// fieldValue 列の値を取得（ゼロ値でデフォルト値があればそれを使う）
+func fieldValue(tx *gorm.DB, f *schema.Field, elem reflect.Value) interface{} {
+	v, isZero := f.ValueOf(tx.Statement.Context, elem)
+	if isZero && f.HasDefaultValue {
+		// 自動採番の列などデフォルト値が式のものは NULL を渡してサーバー側で決めさせる
+		return f.DefaultValueInterface
+	}
+	return v
+}
+
+// ConnLocation 接続のタイムゾーン（DSN の loc）を取得
+//
+// ドライバーは日時パラメータをこのタイムゾーンで送るため、CSV も合わせる。
+func ConnLocation(tx *gorm.DB) *time.Location {
+	if d, ok := tx.Dialector.(*gormmysql.Dialector); ok && d.DSNConfig != nil && d.DSNConfig.Loc != nil {
+		return d.DSNConfig.Loc
+	}
+	return time.UTC
+}
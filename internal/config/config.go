@@ -38,8 +38,8 @@ func LoadDatabaseConfig(env string) (*DatabaseConfig, error) {
 		return loadFromEnv()
 	}
 
-	// 開発・テスト環境はYAMLファイルから読み込み
-	configFile := "configs/database.yaml"
+	// 開発・テスト環境はYAMLファイルから読み込み（パスは ResolvePath で解決）
+	configFile := ResolvePath("configs/database.yaml")
 
 	data, err := os.ReadFile(configFile)
 	if err != nil {
@@ -0,0 +1,41 @@
+// internal/config/path.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RootEnv 設定ファイルなどの相対パスの基準ディレクトリを指定する環境変数
+const RootEnv = "APP_ROOT"
+
+// ResolvePath configs/database.yaml のようなプロジェクトのルートからの相対パスを解決する
+//
+// 絶対パスはそのまま返す。APP_ROOT が設定されていればその下のパスにする。
+// 設定されていなければ作業ディレクトリから親へたどり、最初に存在したパスを返す
+// （cmd/ 以下など、リポジトリ内のどこから実行しても同じファイルを読む）。
+// 見つからなければ path をそのまま返す（読み込み側で存在しないファイルとして扱う）。
+func ResolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if root := os.Getenv(RootEnv); root != "" {
+		return filepath.Join(root, path)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	for {
+		candidate := filepath.Join(dir, path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
+		}
+		dir = parent
+	}
+}
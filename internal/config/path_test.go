@@ -0,0 +1,39 @@
+// internal/config/path_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "cmd", "tool")
+	for _, dir := range []string{filepath.Join(root, "configs"), sub} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(root, "configs", "app.yaml")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(RootEnv, "")
+	t.Chdir(sub)
+	if got := ResolvePath("configs/app.yaml"); got != file {
+		t.Errorf("親ディレクトリのファイル: ResolvePath = %q, 期待値 %q", got, file)
+	}
+	if got := ResolvePath("configs/missing.yaml"); got != "configs/missing.yaml" {
+		t.Errorf("存在しないファイル: ResolvePath = %q", got)
+	}
+	if got := ResolvePath(file); got != file {
+		t.Errorf("絶対パス: ResolvePath = %q", got)
+	}
+
+	t.Setenv(RootEnv, "/srv/app")
+	if got, want := ResolvePath("configs/missing.yaml"), filepath.Join("/srv/app", "configs/missing.yaml"); got != want {
+		t.Errorf("APP_ROOT: ResolvePath = %q, 期待値 %q", got, want)
+	}
+}
@@ -20,6 +20,9 @@ func Migrate(db *gorm.DB) error {
         &models.Tag{},     // 2. タグ（独立）
         &models.Post{},    // 3. 投稿（ユーザーに依存）
         &models.Comment{}, // 4. コメント（ユーザー・投稿に依存）
+        &models.SlugHistory{}, // 5. 投稿・タグの旧スラッグ（外部キーなし）
+        &models.ModerationAction{}, // 6. コメントのモデレーション監査ログ（コメント・ユーザーに依存）
+        &models.CommentDailyRollup{}, // 7. ユーザー別・日別のコメント集計（外部キーなし）
         // 多対多の中間テーブル（post_tags）は自動作成される
     )
 
@@ -37,6 +40,9 @@ func DropAllTables(db *gorm.DB) error {
 
     // 外部キー制約の関係で削除順序が重要
     tables := []interface{}{
+        &models.CommentDailyRollup{},
+        &models.ModerationAction{},
+        &models.SlugHistory{},
         &models.Comment{},
         &models.Post{},
         &models.Tag{},
@@ -0,0 +1,151 @@
+// internal/integrity/checks.go
+package integrity
+
+import (
+	"fmt"
+
+	"go-db-performance-study/internal/models"
+)
+
+// maxChainDepth 循環の検出で親をたどる最大の段数（cte_max_recursion_depth より小さくする）
+const maxChainDepth = 100
+
+// cycleCTE 親をたどって自分に戻るコメントを求める再帰 CTE
+//
+// min_id は循環に含まれるコメントの最小ID。修正時はこのコメントだけをルートにして循環を切る。
+var cycleCTE = fmt.Sprintf(`WITH RECURSIVE chain (start_id, current_id, min_id, depth) AS (
+	SELECT id, parent_id, id, 1 FROM comments WHERE parent_id IS NOT NULL
+	UNION ALL
+	SELECT ch.start_id, c.parent_id, LEAST(ch.min_id, c.id), ch.depth + 1
+	FROM chain ch JOIN comments c ON c.id = ch.current_id
+	WHERE c.parent_id IS NOT NULL AND ch.current_id <> ch.start_id AND ch.depth < %d
+)`, maxChainDepth)
+
+// tagPostCounts post_tags から数えたタグごとの投稿数
+const tagPostCounts = "LEFT JOIN (SELECT tag_id, COUNT(*) AS cnt FROM post_tags GROUP BY tag_id) pt ON pt.tag_id = t.id"
+
+// Checks 全チェックを実行順に取得
+//
+// 修正で行が消えると後続のチェックの対象が変わるため、
+// 孤立した行の削除 → 親子関係 → 重複・値の検証 → 集計値の順に並べる。
+func Checks() []Check {
+	postStatuses := quoteList(string(models.PostStatusDraft), string(models.PostStatusPublished), string(models.PostStatusArchived))
+	commentStatuses := quoteList(string(models.CommentStatusPending), string(models.CommentStatusApproved),
+		string(models.CommentStatusSpam), string(models.CommentStatusDeleted))
+
+	return []Check{
+		sqlCheck("orphan-posts", "存在しないユーザーを参照する投稿",
+			`SELECT CONCAT('post ', p.id, ' → user ', p.user_id) AS detail
+			FROM posts p LEFT JOIN users u ON u.id = p.user_id WHERE u.id IS NULL`,
+			"投稿とそのコメント・タグの関連付けを削除",
+			`DELETE c FROM comments c JOIN posts p ON p.id = c.post_id LEFT JOIN users u ON u.id = p.user_id WHERE u.id IS NULL`,
+			`DELETE pt FROM post_tags pt JOIN posts p ON p.id = pt.post_id LEFT JOIN users u ON u.id = p.user_id WHERE u.id IS NULL`,
+			`DELETE p FROM posts p LEFT JOIN users u ON u.id = p.user_id WHERE u.id IS NULL`),
+
+		sqlCheck("orphan-comments", "存在しない投稿・ユーザーを参照するコメント",
+			`SELECT CONCAT('comment ', c.id, ' → post ', c.post_id, IF(p.id IS NULL, '(なし)', ''),
+				', user ', c.user_id, IF(u.id IS NULL, '(なし)', '')) AS detail
+			FROM comments c LEFT JOIN posts p ON p.id = c.post_id LEFT JOIN users u ON u.id = c.user_id
+			WHERE p.id IS NULL OR u.id IS NULL`,
+			"コメントを削除（返信は orphan-parents でルートコメントになる）",
+			`DELETE c FROM comments c LEFT JOIN posts p ON p.id = c.post_id LEFT JOIN users u ON u.id = c.user_id
+			WHERE p.id IS NULL OR u.id IS NULL`),
+
+		sqlCheck("orphan-parents", "存在しない親コメントへの返信",
+			`SELECT CONCAT('comment ', c.id, ' → parent ', c.parent_id) AS detail
+			FROM comments c LEFT JOIN comments p ON p.id = c.parent_id
+			WHERE c.parent_id IS NOT NULL AND p.id IS NULL`,
+			"parent_id を NULL にしてルートコメントにする",
+			`UPDATE comments c LEFT JOIN comments p ON p.id = c.parent_id SET c.parent_id = NULL
+			WHERE c.parent_id IS NOT NULL AND p.id IS NULL`),
+
+		sqlCheck("orphan-post-tags", "存在しない投稿・タグを参照する post_tags",
+			`SELECT CONCAT('post ', pt.post_id, IF(p.id IS NULL, '(なし)', ''), ' / tag ', pt.tag_id, IF(t.id IS NULL, '(なし)', '')) AS detail
+			FROM post_tags pt LEFT JOIN posts p ON p.id = pt.post_id LEFT JOIN tags t ON t.id = pt.tag_id
+			WHERE p.id IS NULL OR t.id IS NULL`,
+			"関連付けを削除",
+			`DELETE pt FROM post_tags pt LEFT JOIN posts p ON p.id = pt.post_id LEFT JOIN tags t ON t.id = pt.tag_id
+			WHERE p.id IS NULL OR t.id IS NULL`),
+
+		sqlCheck("cross-post-parents", "別の投稿のコメントを親に持つ返信",
+			`SELECT CONCAT('comment ', c.id, ' (post ', c.post_id, ') → parent ', p.id, ' (post ', p.post_id, ')') AS detail
+			FROM comments c JOIN comments p ON p.id = c.parent_id WHERE p.post_id <> c.post_id`,
+			"parent_id を NULL にしてルートコメントにする",
+			`UPDATE comments c JOIN comments p ON p.id = c.parent_id SET c.parent_id = NULL WHERE p.post_id <> c.post_id`),
+
+		sqlCheck("parent-cycles", fmt.Sprintf("親をたどると自分に戻るコメント（%d段まで検査）", maxChainDepth),
+			cycleCTE+`
+			SELECT CONCAT('comment ', start_id, ' (循環の長さ ', depth, ', 最小ID ', min_id, ')') AS detail
+			FROM chain WHERE current_id = start_id`,
+			"循環ごとに最小IDのコメントをルートコメントにする",
+			`UPDATE comments c JOIN (`+cycleCTE+`
+				SELECT DISTINCT min_id FROM chain WHERE current_id = start_id
+			) x ON x.min_id = c.id SET c.parent_id = NULL`),
+
+		// 親子関係を直した後に検査する（path が空の行は作り直し前として対象にしない）
+		sqlCheck("comment-paths", "親の path と一致しない path を持つコメント",
+			`SELECT CONCAT('comment ', c.id, ': ', QUOTE(c.path)) AS detail
+			FROM comments c LEFT JOIN comments p ON p.id = c.parent_id WHERE `+models.CommentPathMismatchSQL(),
+			"ルートコメントから path を作り直す",
+			models.RebuildCommentPathsSQL("TRUE")),
+
+		// 重複した組は (post_id, tag_id) のキーがあれば入らないが、キーが消えたテーブルでも検査できるよう両方を見る。
+		// キーの作成は DDL（暗黙のコミットが起きる）のため -fix では行わず、報告だけにする
+		sqlCheck("post-tags-key", "post_tags に (post_id, tag_id) の主キー・一意インデックスがない",
+			`SELECT 'post_tags: (post_id, tag_id) の主キー・一意インデックスなし（重複を修正してから手動で作成してください）' AS detail FROM DUAL
+			WHERE NOT EXISTS (
+				SELECT 1 FROM information_schema.statistics
+				WHERE table_schema = DATABASE() AND table_name = 'post_tags' AND non_unique = 0
+				GROUP BY index_name
+				HAVING COUNT(*) = 2 AND SUM(column_name IN ('post_id', 'tag_id')) = 2
+			)`,
+			""),
+
+		sqlCheck("duplicate-post-tags", "同じ投稿とタグの組が複数ある post_tags",
+			`SELECT CONCAT('post ', post_id, ' / tag ', tag_id, ' × ', COUNT(*)) AS detail
+			FROM post_tags GROUP BY post_id, tag_id HAVING COUNT(*) > 1`,
+			"重複した組を1行にまとめる",
+			`CREATE TEMPORARY TABLE integrity_post_tag_dups AS
+				SELECT post_id, tag_id FROM post_tags GROUP BY post_id, tag_id HAVING COUNT(*) > 1`,
+			`DELETE pt FROM post_tags pt JOIN integrity_post_tag_dups d ON d.post_id = pt.post_id AND d.tag_id = pt.tag_id`,
+			`INSERT INTO post_tags (post_id, tag_id) SELECT post_id, tag_id FROM integrity_post_tag_dups`,
+			`DROP TEMPORARY TABLE integrity_post_tag_dups`),
+
+		sqlCheck("duplicate-post-slugs", "スラッグが重複する投稿",
+			`SELECT CONCAT('"', slug, '" × ', COUNT(*), ' (id ', GROUP_CONCAT(id ORDER BY id), ')') AS detail
+			FROM posts GROUP BY slug HAVING COUNT(*) > 1`,
+			"最小ID以外のスラッグの末尾に -<id> を付ける",
+			`UPDATE posts p JOIN (SELECT slug, MIN(id) AS keep_id FROM posts GROUP BY slug HAVING COUNT(*) > 1) d ON d.slug = p.slug
+			SET p.slug = CONCAT(LEFT(p.slug, 240), '-', p.id) WHERE p.id <> d.keep_id`),
+
+		sqlCheck("duplicate-tag-slugs", "スラッグが重複するタグ",
+			`SELECT CONCAT('"', slug, '" × ', COUNT(*), ' (id ', GROUP_CONCAT(id ORDER BY id), ')') AS detail
+			FROM tags GROUP BY slug HAVING COUNT(*) > 1`,
+			"最小ID以外のスラッグの末尾に -<id> を付ける",
+			`UPDATE tags t JOIN (SELECT slug, MIN(id) AS keep_id FROM tags GROUP BY slug HAVING COUNT(*) > 1) d ON d.slug = t.slug
+			SET t.slug = CONCAT(LEFT(t.slug, 88), '-', t.id) WHERE t.id <> d.keep_id`),
+
+		// 照合順序が大文字小文字を区別しないため、アプリと同じく完全一致で比較する
+		sqlCheck("invalid-post-status", "定義にないステータスの投稿",
+			`SELECT CONCAT('post ', id, ': ', QUOTE(status)) AS detail
+			FROM posts WHERE status COLLATE utf8mb4_bin NOT IN (`+postStatuses+`)`,
+			fmt.Sprintf("ステータスを %s にする", models.PostStatusDraft),
+			fmt.Sprintf(`UPDATE posts SET status = '%s' WHERE status COLLATE utf8mb4_bin NOT IN (%s)`,
+				models.PostStatusDraft, postStatuses)),
+
+		sqlCheck("invalid-comment-status", "定義にないステータスのコメント",
+			`SELECT CONCAT('comment ', id, ': ', QUOTE(status)) AS detail
+			FROM comments WHERE status COLLATE utf8mb4_bin NOT IN (`+commentStatuses+`)`,
+			fmt.Sprintf("ステータスを %s にして再審査の対象にする", models.CommentStatusPending),
+			fmt.Sprintf(`UPDATE comments SET status = '%s' WHERE status COLLATE utf8mb4_bin NOT IN (%s)`,
+				models.CommentStatusPending, commentStatuses)),
+
+		utf8Check(),
+
+		sqlCheck("tag-post-count", "post_count が post_tags の件数と一致しないタグ",
+			`SELECT CONCAT('tag ', t.id, ': post_count=', t.post_count, ' / 実数=', COALESCE(pt.cnt, 0)) AS detail
+			FROM tags t `+tagPostCounts+` WHERE t.post_count <> COALESCE(pt.cnt, 0)`,
+			"post_tags の件数で更新",
+			`UPDATE tags t `+tagPostCounts+` SET t.post_count = COALESCE(pt.cnt, 0) WHERE t.post_count <> COALESCE(pt.cnt, 0)`),
+	}
+}
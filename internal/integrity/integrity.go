@@ -0,0 +1,176 @@
+// internal/integrity/integrity.go
+package integrity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Check 1種類の整合性チェック
+type Check struct {
+	Name        string
+	Description string
+	// FixNote -fix で行う修正の説明（空の場合は自動修正なし）
+	FixNote string
+
+	// detect 問題の件数と例（最大 limit 件）を返す
+	detect func(db *gorm.DB, limit int) (int64, []string, error)
+	// repair 問題を修正する（外部キー検査を止めたトランザクション内で呼ばれる）
+	repair func(tx *gorm.DB) error
+}
+
+// Fixable 自動修正できるか
+func (c Check) Fixable() bool {
+	return c.repair != nil
+}
+
+// Options チェックの実行設定
+type Options struct {
+	// Fix 問題が見つかったチェックの修正を行う
+	Fix bool
+	// Samples 表示する問題の例の件数
+	Samples int
+	// Only 実行するチェック名（空の場合は全て）
+	Only []string
+}
+
+// Result 1チェック分の結果
+type Result struct {
+	Check   Check
+	Count   int64    // 検出件数
+	Samples []string // 問題の例
+	// Fixed 修正を実行したか
+	Fixed bool
+	// Remaining 修正後に再検査した件数（修正しなかった場合は Count と同じ）
+	Remaining int64
+	Elapsed   time.Duration
+}
+
+// OK 問題が残っていないか
+func (r Result) OK() bool {
+	return r.Remaining == 0
+}
+
+// Run チェックを順に実行する
+//
+// 修正は各チェックの直後に行い、その結果を次のチェックが検査する。
+// 例えば孤立コメントを削除して親を失った返信は、後続の orphan-parents で検出される。
+func Run(db *gorm.DB, opts Options) ([]Result, error) {
+	checks, err := selectChecks(opts.Only)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		start := time.Now()
+		count, samples, err := c.detect(db, opts.Samples)
+		if err != nil {
+			return nil, fmt.Errorf("%s の検査エラー: %w", c.Name, err)
+		}
+		r := Result{Check: c, Count: count, Samples: samples, Remaining: count}
+
+		if opts.Fix && count > 0 && c.Fixable() {
+			if err := fix(db, c); err != nil {
+				return nil, fmt.Errorf("%s の修正エラー: %w", c.Name, err)
+			}
+			r.Fixed = true
+			if r.Remaining, _, err = c.detect(db, 0); err != nil {
+				return nil, fmt.Errorf("%s の再検査エラー: %w", c.Name, err)
+			}
+		}
+
+		r.Elapsed = time.Since(start)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// fix 外部キー検査を止めたトランザクションで修正を実行
+//
+// 孤立した行の削除では、削除する行同士が参照し合う（返信と親コメントなど）ため検査を止める。
+// 一意性の検査は止めない（スラッグの付け直しで重複を作らないことを保証するため）。
+func fix(db *gorm.DB, c Check) error {
+	return db.Transaction(func(tx *gorm.DB) (err error) {
+		if err := tx.Exec("SET SESSION foreign_key_checks = 0").Error; err != nil {
+			return fmt.Errorf("外部キー検査の無効化エラー: %w", err)
+		}
+		defer func() {
+			if restoreErr := tx.Exec("SET SESSION foreign_key_checks = 1").Error; restoreErr != nil && err == nil {
+				err = fmt.Errorf("外部キー検査の再有効化エラー: %w", restoreErr)
+			}
+		}()
+		return c.repair(tx)
+	})
+}
+
+// selectChecks 名前でチェックを絞り込む
+func selectChecks(names []string) ([]Check, error) {
+	all := Checks()
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	byName := make(map[string]Check, len(all))
+	for _, c := range all {
+		byName[c.Name] = c
+	}
+	selected := make([]Check, 0, len(names))
+	for _, name := range names {
+		c, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("未知のチェック: %s", name)
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+// sqlCheck SQL で検査・修正するチェックを作成
+//
+// query は問題1件につき1行を返し、detail 列に内容を表す文字列を持つ SELECT。
+// fixes は修正用の SQL で、1トランザクション内で順に実行する。
+func sqlCheck(name, description, query, fixNote string, fixes ...string) Check {
+	c := Check{
+		Name:        name,
+		Description: description,
+		FixNote:     fixNote,
+		detect: func(db *gorm.DB, limit int) (int64, []string, error) {
+			var count int64
+			if err := db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM (%s) q", query)).Scan(&count).Error; err != nil {
+				return 0, nil, err
+			}
+			if count == 0 || limit <= 0 {
+				return count, nil, nil
+			}
+			var samples []string
+			if err := db.Raw(fmt.Sprintf("SELECT detail FROM (%s) q LIMIT %d", query, limit)).Scan(&samples).Error; err != nil {
+				return 0, nil, err
+			}
+			return count, samples, nil
+		},
+	}
+	if len(fixes) > 0 {
+		c.repair = func(tx *gorm.DB) error {
+			for _, stmt := range fixes {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return c
+}
+
+// quoteList 文字列を SQL の IN 句用に並べる
+func quoteList(values ...string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
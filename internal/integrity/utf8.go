@@ -0,0 +1,129 @@
+// internal/integrity/utf8.go
+package integrity
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// textTable UTF-8 を検査するテーブルと列
+type textTable struct {
+	Name    string
+	Columns []string
+}
+
+// textTables UTF-8 を検査する列（生成データや利用者の入力が入る列）
+var textTables = []textTable{
+	{Name: "users", Columns: []string{"name"}},
+	{Name: "tags", Columns: []string{"name", "description"}},
+	{Name: "posts", Columns: []string{"title", "body", "excerpt"}},
+	{Name: "comments", Columns: []string{"body", "user_agent"}},
+}
+
+// textIssue 不正な文字を含む値
+type textIssue struct {
+	Table  string
+	Column string
+	ID     uint64
+	Reason string
+	Fixed  string // 修正後の値
+}
+
+// utf8Check 不正な UTF-8 と置換文字 (U+FFFD) を検出するチェック
+//
+// バイト単位の切り詰めで多バイト文字が途中で切れた値や、
+// 文字コード変換に失敗して U+FFFD に置き換わった値を対象にする。
+// SQL では判定できないため、各テーブルを主キー順に読んで Go 側で検査する。
+func utf8Check() Check {
+	return Check{
+		Name:        "invalid-utf8",
+		Description: "不正な UTF-8 または置換文字 (U+FFFD) を含むテキスト",
+		FixNote:     "不正なバイト列と置換文字を取り除く",
+		detect: func(db *gorm.DB, limit int) (int64, []string, error) {
+			var count int64
+			var samples []string
+			err := scanTextIssues(db, func(issue textIssue) {
+				count++
+				if len(samples) < limit {
+					samples = append(samples, fmt.Sprintf("%s %d.%s: %s", issue.Table, issue.ID, issue.Column, issue.Reason))
+				}
+			})
+			return count, samples, err
+		},
+		repair: func(tx *gorm.DB) error {
+			// 読み込み中は同じ接続で更新できないため、修正内容を集めてから更新する
+			var issues []textIssue
+			if err := scanTextIssues(tx, func(issue textIssue) { issues = append(issues, issue) }); err != nil {
+				return err
+			}
+			for _, issue := range issues {
+				err := tx.Exec(fmt.Sprintf("UPDATE `%s` SET `%s` = ? WHERE id = ?", issue.Table, issue.Column),
+					issue.Fixed, issue.ID).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// scanTextIssues 全テキスト列を読み、問題のある値ごとに fn を呼ぶ
+func scanTextIssues(db *gorm.DB, fn func(textIssue)) error {
+	for _, t := range textTables {
+		if err := scanTable(db, t, fn); err != nil {
+			return fmt.Errorf("%s の検査エラー: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// scanTable 1テーブル分のテキスト列を検査
+func scanTable(db *gorm.DB, t textTable, fn func(textIssue)) error {
+	rows, err := db.Raw(fmt.Sprintf("SELECT id, `%s` FROM `%s` ORDER BY id",
+		strings.Join(t.Columns, "`, `"), t.Name)).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var id uint64
+	values := make([]sql.RawBytes, len(t.Columns))
+	pointers := make([]interface{}, len(t.Columns)+1)
+	pointers[0] = &id
+	for i := range values {
+		pointers[i+1] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			reason, fixed, ok := inspectText(string(v))
+			if ok {
+				continue
+			}
+			fn(textIssue{Table: t.Name, Column: t.Columns[i], ID: id, Reason: reason, Fixed: fixed})
+		}
+	}
+	return rows.Err()
+}
+
+// inspectText 値を検査し、問題があれば理由と修正後の値を返す
+func inspectText(s string) (reason string, fixed string, ok bool) {
+	switch {
+	case !utf8.ValidString(s):
+		reason = "不正な UTF-8"
+	case strings.ContainsRune(s, utf8.RuneError):
+		reason = "置換文字 (U+FFFD)"
+	default:
+		return "", s, true
+	}
+	fixed = strings.ReplaceAll(strings.ToValidUTF8(s, ""), string(utf8.RuneError), "")
+	return reason, fixed, false
+}
@@ -0,0 +1,195 @@
+// internal/ipreputation/reputation.go
+package ipreputation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config 許可・拒否リストのファイル
+//
+// ファイルは1行に CIDR（203.0.113.0/24, 2001:db8::/32）か単一のアドレスを書く。
+// # 以降はコメント。許可と拒否の両方に一致する場合は、より長いプレフィックスの評価を使う
+// （拒否した範囲の一部だけを許可できる）。
+type Config struct {
+	AllowFiles []string
+	DenyFiles  []string
+}
+
+// fileStamp 変更の検出に使うファイルの状態
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reputation リストのファイルから作った表（Reload で差し替えられる）
+type Reputation struct {
+	config Config
+	table  atomic.Pointer[Table]
+
+	mu     sync.Mutex // Reload の直列化
+	stamps map[string]fileStamp
+}
+
+// Load リストのファイルを読み込む
+func Load(config Config) (*Reputation, error) {
+	r := &Reputation{config: config}
+	if _, err := r.reload(true); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Table 現在の表
+func (r *Reputation) Table() *Table {
+	return r.table.Load()
+}
+
+// Lookup addr を含む最も長いプレフィックスのエントリ
+func (r *Reputation) Lookup(addr netip.Addr) (Entry, bool) {
+	return r.table.Load().Lookup(addr)
+}
+
+// Check 文字列の IP アドレスの評価（解析できないアドレスは Unknown）
+func (r *Reputation) Check(ip string) Verdict {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return Unknown
+	}
+	e, ok := r.Lookup(addr)
+	if !ok {
+		return Unknown
+	}
+	return e.Verdict
+}
+
+// Reload ファイルが変更されていれば読み込み直し、差し替えたかを返す
+//
+// 読み込みに失敗した場合は以前の表を使い続ける。
+func (r *Reputation) Reload() (bool, error) {
+	return r.reload(false)
+}
+
+// Watch interval ごとにファイルの変更を確認し、変更があれば読み込み直す（ctx が終わるまで戻らない）
+func (r *Reputation) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				log.Printf("IP リストの再読み込みエラー（以前のリストを使います）: %v", err)
+			} else if reloaded {
+				log.Printf("IP リストを再読み込みしました（%d件）", r.Table().Len())
+			}
+		}
+	}
+}
+
+// reload force でなければ、ファイルの更新日時・サイズが変わった場合だけ読み込む
+func (r *Reputation) reload(force bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps := map[string]fileStamp{}
+	changed := force
+	for _, path := range r.files() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("IP リストの確認エラー: %w", err)
+		}
+		stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		if stamps[path] != r.stamps[path] {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	var entries []Entry
+	lists := []struct {
+		files   []string
+		verdict Verdict
+	}{
+		{r.config.AllowFiles, Allow},
+		{r.config.DenyFiles, Deny},
+	}
+	for _, l := range lists {
+		for _, path := range l.files {
+			e, err := ReadList(path, l.verdict)
+			if err != nil {
+				return false, err
+			}
+			entries = append(entries, e...)
+		}
+	}
+
+	r.table.Store(NewTable(entries))
+	r.stamps = stamps
+	return true, nil
+}
+
+// files 全てのリストのファイル
+func (r *Reputation) files() []string {
+	return append(append([]string{}, r.config.AllowFiles...), r.config.DenyFiles...)
+}
+
+// ReadList リストのファイルを読む（Source はファイル名:行番号）
+func ReadList(path string, verdict Verdict) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("IP リストの読み込みエラー: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	name := filepath.Base(path)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		prefix, err := ParsePrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		entries = append(entries, Entry{Prefix: prefix, Verdict: verdict, Source: fmt.Sprintf("%s:%d", name, n)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("IP リストの読み込みエラー (%s): %w", path, err)
+	}
+	return entries, nil
+}
+
+// ParsePrefix CIDR か単一のアドレス（/32・/128 とみなす）を解析する
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("CIDR の形式が不正です: %s", s)
+		}
+		return p.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("IP アドレスの形式が不正です: %s", s)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,114 @@
+// internal/ipreputation/reputation_test.go
+package ipreputation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{"203.0.113.0/24", "203.0.113.0/24", false},
+		{"203.0.113.9/24", "203.0.113.0/24", false},
+		{"203.0.113.9", "203.0.113.9/32", false},
+		{"2001:db8::1", "2001:db8::1/128", false},
+		{"2001:db8::/32", "2001:db8::/32", false},
+		{"203.0.113.0/33", "", true},
+		{"example.com", "", true},
+	}
+	for _, tt := range tests {
+		p, err := ParsePrefix(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePrefix(%q) のエラー = %v, エラーの期待値 %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if err == nil && p.String() != tt.want {
+			t.Errorf("ParsePrefix(%q) = %s, 期待値 %s", tt.s, p, tt.want)
+		}
+	}
+}
+
+// writeList テスト用のリストのファイルを作る
+func writeList(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadList(t *testing.T) {
+	path := writeList(t, t.TempDir(), "deny.txt", "# 拒否リスト\n\n203.0.113.0/24  # 検証用\n  2001:db8::1\n")
+	entries, err := ReadList(path, Deny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("%d件, 期待値 2件: %v", len(entries), entries)
+	}
+	if entries[0].Prefix.String() != "203.0.113.0/24" || entries[0].Source != "deny.txt:3" || entries[0].Verdict != Deny {
+		t.Errorf("1件目 = %+v", entries[0])
+	}
+	if entries[1].Prefix.String() != "2001:db8::1/128" || entries[1].Source != "deny.txt:4" {
+		t.Errorf("2件目 = %+v", entries[1])
+	}
+
+	bad := writeList(t, t.TempDir(), "bad.txt", "10.0.0.0/8\nnot-an-ip\n")
+	if _, err := ReadList(bad, Deny); err == nil {
+		t.Error("不正な行でエラーになりません")
+	}
+}
+
+func TestLoadAndReload(t *testing.T) {
+	dir := t.TempDir()
+	allow := writeList(t, dir, "allow.txt", "203.0.113.128/25\n")
+	deny := writeList(t, dir, "deny.txt", "203.0.113.0/24\n")
+
+	r, err := Load(Config{AllowFiles: []string{allow}, DenyFiles: []string{deny}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]Verdict{
+		"203.0.113.1":   Deny,
+		"203.0.113.200": Allow,
+		" 203.0.113.1 ": Deny,
+		"198.51.100.1":  Unknown,
+		"not-an-ip":     Unknown,
+	}
+	for ip, want := range checks {
+		if got := r.Check(ip); got != want {
+			t.Errorf("Check(%q) = %s, 期待値 %s", ip, got, want)
+		}
+	}
+
+	if reloaded, err := r.Reload(); err != nil || reloaded {
+		t.Errorf("変更がないのに再読み込みしました: %v %v", reloaded, err)
+	}
+
+	writeList(t, dir, "deny.txt", "203.0.113.0/24\n198.51.100.0/24\n")
+	if reloaded, err := r.Reload(); err != nil || !reloaded {
+		t.Fatalf("変更後に再読み込みしません: %v %v", reloaded, err)
+	}
+	if got := r.Check("198.51.100.1"); got != Deny {
+		t.Errorf("再読み込み後の Check = %s, 期待値 deny", got)
+	}
+
+	// 読み込みに失敗した場合は以前の表を使い続ける
+	writeList(t, dir, "deny.txt", "broken\n")
+	if _, err := r.Reload(); err == nil {
+		t.Error("不正なファイルでエラーになりません")
+	}
+	if got := r.Check("198.51.100.1"); got != Deny {
+		t.Errorf("失敗後に以前の表が使われていません: %s", got)
+	}
+
+	if _, err := Load(Config{DenyFiles: []string{filepath.Join(dir, "missing.txt")}}); err == nil {
+		t.Error("存在しないファイルでエラーになりません")
+	}
+}
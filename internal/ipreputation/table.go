@@ -0,0 +1,133 @@
+// internal/ipreputation/table.go
+package ipreputation
+
+import (
+	"net/netip"
+)
+
+// Verdict IP アドレスの評価
+type Verdict int
+
+const (
+	Unknown Verdict = iota // どのリストにもない
+	Allow                  // 許可リストに一致
+	Deny                   // 拒否リストに一致
+)
+
+// String 評価の名前
+func (v Verdict) String() string {
+	switch v {
+	case Allow:
+		return "allow"
+	case Deny:
+		return "deny"
+	}
+	return "unknown"
+}
+
+// Entry リストの1行（CIDR と評価）
+type Entry struct {
+	Prefix  netip.Prefix
+	Verdict Verdict
+	Source  string // 定義元（ファイル名:行番号）
+}
+
+// node 2分木の節（ビットごとに左右へ分岐する）
+type node struct {
+	child [2]*node
+	entry *Entry // この節までのビット列がプレフィックスと一致するエントリ
+}
+
+// Table CIDR の最長一致で IP アドレスを引く表（IPv4 と IPv6 で別の木を持つ）
+//
+// 作成後は読み取り専用なので、複数のゴルーチンから同時に引ける。
+type Table struct {
+	v4, v6 *node
+	size   int
+}
+
+// NewTable エントリから表を作る
+//
+// 同じプレフィックスが重複する場合は拒否を優先する。
+// IPv4 射影アドレス（::ffff:a.b.c.d）のプレフィックスは IPv4 として扱う。
+func NewTable(entries []Entry) *Table {
+	t := &Table{v4: &node{}, v6: &node{}}
+	for i := range entries {
+		t.insert(entries[i])
+	}
+	return t
+}
+
+// insert エントリを木に追加
+func (t *Table) insert(e Entry) {
+	e.Prefix = normalizePrefix(e.Prefix)
+	if !e.Prefix.IsValid() {
+		return
+	}
+	n := t.root(e.Prefix.Addr())
+	bytes := e.Prefix.Addr().AsSlice()
+	for i := 0; i < e.Prefix.Bits(); i++ {
+		b := bit(bytes, i)
+		if n.child[b] == nil {
+			n.child[b] = &node{}
+		}
+		n = n.child[b]
+	}
+	if n.entry == nil {
+		t.size++
+	} else if n.entry.Verdict == Deny {
+		return
+	}
+	n.entry = &e
+}
+
+// Lookup addr を含む最も長いプレフィックスのエントリ（なければ false）
+func (t *Table) Lookup(addr netip.Addr) (Entry, bool) {
+	if !addr.IsValid() {
+		return Entry{}, false
+	}
+	addr = addr.Unmap()
+	n := t.root(addr)
+	bytes := addr.AsSlice()
+
+	var found *Entry
+	for i := 0; n != nil; i++ {
+		if n.entry != nil {
+			found = n.entry
+		}
+		if i == len(bytes)*8 {
+			break
+		}
+		n = n.child[bit(bytes, i)]
+	}
+	if found == nil {
+		return Entry{}, false
+	}
+	return *found, true
+}
+
+// Len 表のエントリ数（重複を除く）
+func (t *Table) Len() int {
+	return t.size
+}
+
+// root アドレスの種類に応じた木
+func (t *Table) root(addr netip.Addr) *node {
+	if addr.Is4() {
+		return t.v4
+	}
+	return t.v6
+}
+
+// normalizePrefix ホスト部を 0 にし、IPv4 射影アドレスは IPv4 のプレフィックスにする
+func normalizePrefix(p netip.Prefix) netip.Prefix {
+	if addr := p.Addr(); addr.Is4In6() && p.Bits() >= 96 {
+		p = netip.PrefixFrom(addr.Unmap(), p.Bits()-96)
+	}
+	return p.Masked()
+}
+
+// bit 先頭から i ビット目（0 または 1）
+func bit(bytes []byte, i int) int {
+	return int(bytes[i/8]>>(7-uint(i%8))) & 1
+}
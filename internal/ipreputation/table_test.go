@@ -0,0 +1,88 @@
+// internal/ipreputation/table_test.go
+package ipreputation
+
+import (
+	"net/netip"
+	"testing"
+)
+
+// entry テスト用のエントリ
+func entry(cidr string, verdict Verdict) Entry {
+	return Entry{Prefix: netip.MustParsePrefix(cidr), Verdict: verdict, Source: cidr}
+}
+
+func TestTableLookup(t *testing.T) {
+	table := NewTable([]Entry{
+		entry("203.0.113.0/24", Deny),
+		entry("203.0.113.128/25", Allow), // 拒否した範囲の一部を許可
+		entry("203.0.113.200/32", Deny),
+		entry("198.51.100.7/16", Allow), // ホスト部は無視される
+		entry("2001:db8::/32", Deny),
+		entry("2001:db8:1::/48", Allow),
+		entry("::ffff:192.0.2.0/120", Deny), // IPv4 射影アドレスは IPv4 として扱う
+	})
+
+	tests := []struct {
+		addr    string
+		want    Verdict
+		wantSrc string
+	}{
+		{"203.0.113.5", Deny, "203.0.113.0/24"},
+		{"203.0.113.130", Allow, "203.0.113.128/25"},
+		{"203.0.113.200", Deny, "203.0.113.200/32"},
+		{"198.51.0.1", Allow, "198.51.100.7/16"},
+		{"192.0.2.10", Deny, "::ffff:192.0.2.0/120"},
+		{"::ffff:203.0.113.5", Deny, "203.0.113.0/24"},
+		{"2001:db8:2::1", Deny, "2001:db8::/32"},
+		{"2001:db8:1::1", Allow, "2001:db8:1::/48"},
+		{"203.0.114.1", Unknown, ""},
+		{"2001:db9::1", Unknown, ""},
+	}
+	for _, tt := range tests {
+		e, ok := table.Lookup(netip.MustParseAddr(tt.addr))
+		got := Unknown
+		if ok {
+			got = e.Verdict
+		}
+		if got != tt.want || e.Source != tt.wantSrc {
+			t.Errorf("Lookup(%s) = %s (%q), 期待値 %s (%q)", tt.addr, got, e.Source, tt.want, tt.wantSrc)
+		}
+	}
+
+	if _, ok := table.Lookup(netip.Addr{}); ok {
+		t.Error("不正なアドレスが一致しました")
+	}
+}
+
+func TestTableDuplicatePrefixPrefersDeny(t *testing.T) {
+	for _, entries := range [][]Entry{
+		{entry("10.0.0.0/8", Allow), entry("10.0.0.0/8", Deny)},
+		{entry("10.0.0.0/8", Deny), entry("10.1.2.3/8", Allow)},
+	} {
+		table := NewTable(entries)
+		if table.Len() != 1 {
+			t.Errorf("Len() = %d, 期待値 1", table.Len())
+		}
+		if e, _ := table.Lookup(netip.MustParseAddr("10.9.9.9")); e.Verdict != Deny {
+			t.Errorf("同じプレフィックスで拒否が優先されません: %s", e.Verdict)
+		}
+	}
+}
+
+func TestTableMatchAll(t *testing.T) {
+	table := NewTable([]Entry{entry("0.0.0.0/0", Deny), entry("::/0", Allow)})
+	if e, ok := table.Lookup(netip.MustParseAddr("8.8.8.8")); !ok || e.Verdict != Deny {
+		t.Errorf("0.0.0.0/0 に一致しません: %v %v", e, ok)
+	}
+	if e, ok := table.Lookup(netip.MustParseAddr("2001:4860::8888")); !ok || e.Verdict != Allow {
+		t.Errorf("::/0 に一致しません: %v %v", e, ok)
+	}
+}
+
+func TestVerdictString(t *testing.T) {
+	for v, want := range map[Verdict]string{Unknown: "unknown", Allow: "allow", Deny: "deny"} {
+		if got := v.String(); got != want {
+			t.Errorf("%d.String() = %q, 期待値 %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// internal/maintenance/comment_paths.go
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	"go-db-performance-study/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CommentPathStat path の作り直しの結果
+type CommentPathStat struct {
+	Rows    int64 // path を更新したコメント数
+	Chunks  int   // 実行した UPDATE の回数
+	Elapsed time.Duration
+}
+
+// BackfillCommentPaths ルートコメントの ID の範囲ごとに、スレッド全体の path を作り直す
+//
+// path が既に正しい行は更新しない。1回の UPDATE がロックする行を抑えるため、
+// ルートコメントを chunkSize 件の ID の範囲に分けて実行する。
+func BackfillCommentPaths(db *gorm.DB, chunkSize int) (CommentPathStat, error) {
+	var stat CommentPathStat
+	if chunkSize <= 0 {
+		return stat, fmt.Errorf("chunk-size は 1 以上を指定してください: %d", chunkSize)
+	}
+	if !db.Migrator().HasColumn(&models.Comment{}, "path") {
+		return stat, fmt.Errorf("列 comments.path が存在しません（マイグレーションを実行してください）")
+	}
+
+	var bounds struct {
+		MinID *uint
+		MaxID *uint
+	}
+	err := db.Model(&models.Comment{}).
+		Select("MIN(id) AS min_id, MAX(id) AS max_id").
+		Where("parent_id IS NULL").
+		Scan(&bounds).Error
+	if err != nil {
+		return stat, fmt.Errorf("ルートコメントの範囲の取得エラー: %w", err)
+	}
+	if bounds.MinID == nil {
+		return stat, nil
+	}
+
+	start := time.Now()
+	query := models.RebuildCommentPathsSQL("r.id BETWEEN ? AND ?")
+	for lo := *bounds.MinID; lo <= *bounds.MaxID; lo += uint(chunkSize) {
+		hi := lo + uint(chunkSize) - 1
+		result := db.Exec(query, lo, hi)
+		if result.Error != nil {
+			return stat, fmt.Errorf("path の作り直しエラー (ID %d〜%d): %w", lo, hi, result.Error)
+		}
+		stat.Rows += result.RowsAffected
+		stat.Chunks++
+	}
+	stat.Elapsed = time.Since(start)
+	return stat, nil
+}
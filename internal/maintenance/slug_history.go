@@ -0,0 +1,106 @@
+// internal/maintenance/slug_history.go
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/slug"
+
+	"gorm.io/gorm"
+)
+
+// SlugHistoryOptions slug 履歴の削除条件
+//
+// 行がなくなった履歴と、現在の slug と同じで辿られない履歴は常に削除する。
+type SlugHistoryOptions struct {
+	// OlderThan これより前に記録した履歴を削除する（0 の場合は期間で削除しない）
+	OlderThan time.Duration
+	// Keep 投稿・タグごとに新しい順で残す件数（0 の場合は件数で削除しない）
+	Keep int
+	// DryRun 削除せずに件数だけを数える
+	DryRun bool
+}
+
+// PruneStat 削除条件ごとの結果
+type PruneStat struct {
+	Rule    string
+	Rows    int64 // 削除した行数（DryRun の場合は対象の行数）
+	Elapsed time.Duration
+}
+
+// pruneRule slug 履歴（別名 h）の削除条件
+type pruneRule struct {
+	name  string
+	join  string
+	where string
+	args  []interface{}
+}
+
+// PruneSlugHistory 不要になった slug 履歴を削除する
+func PruneSlugHistory(db *gorm.DB, opts SlugHistoryOptions) ([]PruneStat, error) {
+	if opts.OlderThan < 0 || opts.Keep < 0 {
+		return nil, fmt.Errorf("削除条件が不正です: older-than=%v keep=%d", opts.OlderThan, opts.Keep)
+	}
+	if !db.Migrator().HasTable(&models.SlugHistory{}) {
+		return nil, fmt.Errorf("テーブル slug_histories が存在しません（マイグレーションを実行してください）")
+	}
+
+	var rules []pruneRule
+	for _, scope := range []slug.Scope{slug.Posts, slug.Tags} {
+		rules = append(rules,
+			pruneRule{
+				name:  fmt.Sprintf("%s: 削除済み", scope.Table),
+				join:  fmt.Sprintf("LEFT JOIN `%s` e ON e.id = h.entity_id", scope.Table),
+				where: "h.entity_type = ? AND e.id IS NULL",
+				args:  []interface{}{scope.Table},
+			},
+			pruneRule{
+				name:  fmt.Sprintf("%s: 現在の slug と重複", scope.Table),
+				join:  fmt.Sprintf("JOIN `%s` e ON e.%s = h.slug", scope.Table, scope.Column),
+				where: "h.entity_type = ?",
+				args:  []interface{}{scope.Table},
+			},
+		)
+	}
+	if opts.OlderThan > 0 {
+		rules = append(rules, pruneRule{
+			name:  fmt.Sprintf("%v より前", opts.OlderThan),
+			where: "h.created_at < ?",
+			args:  []interface{}{time.Now().Add(-opts.OlderThan)},
+		})
+	}
+	if opts.Keep > 0 {
+		rules = append(rules, pruneRule{
+			name: fmt.Sprintf("新しい %d件より後", opts.Keep),
+			join: "JOIN (SELECT id, ROW_NUMBER() OVER (PARTITION BY entity_type, entity_id ORDER BY created_at DESC, id DESC) AS rn " +
+				"FROM slug_histories) r ON r.id = h.id",
+			where: "r.rn > ?",
+			args:  []interface{}{opts.Keep},
+		})
+	}
+
+	stats := make([]PruneStat, 0, len(rules))
+	for _, rule := range rules {
+		start := time.Now()
+		rows, err := rule.run(db, opts.DryRun)
+		if err != nil {
+			return stats, fmt.Errorf("slug 履歴の削除エラー (%s): %w", rule.name, err)
+		}
+		stats = append(stats, PruneStat{Rule: rule.name, Rows: rows, Elapsed: time.Since(start)})
+	}
+	return stats, nil
+}
+
+// run 条件に一致する履歴を削除し、行数を返す（dryRun の場合は数えるだけ）
+func (p pruneRule) run(db *gorm.DB, dryRun bool) (int64, error) {
+	from := "slug_histories h " + p.join
+	if dryRun {
+		var count int64
+		err := db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", from, p.where), p.args...).Scan(&count).Error
+		return count, err
+	}
+	result := db.Exec(fmt.Sprintf("DELETE h FROM %s WHERE %s", from, p.where), p.args...)
+	return result.RowsAffected, result.Error
+}
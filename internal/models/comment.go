@@ -1,10 +1,14 @@
 package models
 
 import (
+	"fmt"
 	"html"
-	"strings"
 	"time"
 
+	"go-db-performance-study/internal/ipreputation"
+	"go-db-performance-study/internal/moderation"
+	"go-db-performance-study/internal/textutil"
+
 	"gorm.io/gorm"
 )
 
@@ -28,6 +32,8 @@ type Comment struct {
     Status    CommentStatus `gorm:"size:20;not null;default:pending;index:idx_comment_status" json:"status" validate:"required,oneof=pending approved spam deleted"`
     IPAddress string        `gorm:"size:45;index:idx_comment_ip" json:"ip_address,omitempty"`
     UserAgent string        `gorm:"size:500" json:"user_agent,omitempty"`
+    ContentHash string      `gorm:"size:64;index:idx_comment_content_hash" json:"-"` // 重複判定用（正規化した本文の SHA-256）
+    Path      string        `gorm:"size:255;not null;default:'';index:idx_comment_path" json:"-"` // マテリアライズドパス（CommentPath の形式。空は未作成）
     IsEdited  bool          `gorm:"default:false" json:"is_edited"`
     EditedAt  *time.Time    `gorm:"null" json:"edited_at,omitempty"`
     CreatedAt time.Time     `gorm:"autoCreateTime;index:idx_comment_created_at" json:"created_at"`
@@ -38,6 +44,9 @@ type Comment struct {
     User    User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty" validate:"-"`
     Parent  *Comment  `gorm:"foreignKey:ParentID" json:"parent,omitempty" validate:"-"`
     Replies []Comment `gorm:"foreignKey:ParentID" json:"replies,omitempty" validate:"-"`
+
+    // Moderation 作成時の自動モデレーションの判定結果（保存しない）
+    Moderation *moderation.Result `gorm:"-" json:"moderation,omitempty" validate:"-"`
 }
 
 // TableName テーブル名を明示的に指定
@@ -100,7 +109,21 @@ type UserCommentActivity struct {
 }
 
 // BeforeCreate 作成前処理
+//
+// ステータスが未指定の場合は自動モデレーションで決める（判定結果は c.Moderation に残る）。
 func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+    // 重複判定用のハッシュはエスケープ前の本文から計算する
+    if c.ContentHash == "" {
+        c.ContentHash = moderation.ContentHash(c.Body)
+    }
+    if c.Status == "" {
+        result, err := c.AutoModerate(tx)
+        if err != nil {
+            return err
+        }
+        c.Status = CommentStatus(result.Status)
+    }
+
     // HTMLエスケープ処理
     c.Body = html.EscapeString(c.Body)
     
@@ -108,14 +131,51 @@ func (c *Comment) BeforeCreate(tx *gorm.DB) error {
     return c.Validate()
 }
 
+// AfterCreate 作成後処理
+//
+// ID が決まってから path を設定する（データ生成のように作成前に設定済みの場合は何もしない）。
+// 親の path が未作成の場合は空のまま残す（maintenance backfill-comment-paths で作る）。
+// MaxThreadDepth より深い返信も空のまま残す（どの読み込み方法でもスレッドに含めない）。
+func (c *Comment) AfterCreate(tx *gorm.DB) error {
+    if c.Path != "" || c.ID == 0 {
+        return nil
+    }
+    db := tx.Session(&gorm.Session{NewDB: true})
+
+    parentPath := ""
+    if c.ParentID != nil {
+        var paths []string
+        if err := db.Model(&Comment{}).Where("id = ?", *c.ParentID).Pluck("path", &paths).Error; err != nil {
+            return fmt.Errorf("親コメントの path の取得エラー: %w", err)
+        }
+        if len(paths) == 0 || paths[0] == "" {
+            return nil
+        }
+        parentPath = paths[0]
+    }
+
+    path, err := CommentPath(parentPath, c.ID)
+    if err != nil {
+        return nil // MaxThreadDepth より深い返信は path を持たない（作成は失敗させない）
+    }
+    if err := db.Model(c).UpdateColumn("path", path).Error; err != nil {
+        return fmt.Errorf("コメントの path の設定エラー: %w", err)
+    }
+    c.Path = path
+    return nil
+}
+
 // BeforeUpdate 更新前処理
 func (c *Comment) BeforeUpdate(tx *gorm.DB) error {
     // 本文が更新された場合
+    // Updates(map) の場合もエスケープ・ハッシュが反映されるよう SetColumn で設定する
     if tx.Statement.Changed("body") {
-        c.Body = html.EscapeString(c.Body)
-        c.IsEdited = true
+        body := updatedString(tx, "body", c.Body)
         now := time.Now()
-        c.EditedAt = &now
+        tx.Statement.SetColumn("content_hash", moderation.ContentHash(body))
+        tx.Statement.SetColumn("body", html.EscapeString(body))
+        tx.Statement.SetColumn("is_edited", true)
+        tx.Statement.SetColumn("edited_at", &now)
     }
     
     return nil
@@ -152,40 +212,49 @@ func (c *Comment) IsDeleted() bool {
 }
 
 // GetPlainBody HTMLタグを除去した本文を取得
+//
+// 本文は作成時にエスケープしているため、元に戻してからタグを除去する。
 func (c *Comment) GetPlainBody() string {
-    // 簡易的なHTMLタグ除去
-    body := c.Body
-    body = strings.ReplaceAll(body, "&lt;", "<")
-    body = strings.ReplaceAll(body, "&gt;", ">")
-    body = strings.ReplaceAll(body, "&amp;", "&")
-    return body
+    return textutil.StripTags(html.UnescapeString(c.Body))
 }
 
-// GetExcerpt 抜粋を取得
+// GetExcerpt 抜粋を取得（length は省略記号を含む最大文字数）
 func (c *Comment) GetExcerpt(length int) string {
-    plainBody := c.GetPlainBody()
-    if len(plainBody) <= length {
-        return plainBody
-    }
-    return plainBody[:length] + "..."
+    return textutil.Excerpt(c.GetPlainBody(), length)
 }
 
-// Approve コメントを承認
-func (c *Comment) Approve(tx *gorm.DB) error {
-    c.Status = CommentStatusApproved
-    return tx.Model(c).UpdateColumn("status", c.Status).Error
+// Approve コメントを承認（監査ログを残す。moderatorID が 0 の場合は自動処理として記録）
+func (c *Comment) Approve(tx *gorm.DB, moderatorID uint, reason string) error {
+    return c.Moderate(tx, CommentStatusApproved, moderatorID, reason)
 }
 
-// MarkAsSpam スパムとしてマーク
-func (c *Comment) MarkAsSpam(tx *gorm.DB) error {
-    c.Status = CommentStatusSpam
-    return tx.Model(c).UpdateColumn("status", c.Status).Error
+// MarkAsSpam スパムとしてマーク（監査ログを残す）
+func (c *Comment) MarkAsSpam(tx *gorm.DB, moderatorID uint, reason string) error {
+    return c.Moderate(tx, CommentStatusSpam, moderatorID, reason)
 }
 
-// SoftDelete ソフトデリート
-func (c *Comment) SoftDelete(tx *gorm.DB) error {
-    c.Status = CommentStatusDeleted
-    return tx.Model(c).UpdateColumn("status", c.Status).Error
+// SoftDelete ソフトデリート（監査ログを残す）
+func (c *Comment) SoftDelete(tx *gorm.DB, moderatorID uint, reason string) error {
+    return c.Moderate(tx, CommentStatusDeleted, moderatorID, reason)
+}
+
+// Moderate ステータスを変更し、変更前後のステータスと理由を監査ログに残す
+//
+// 読み込んだ時点のステータス（c.Status）から変わっていない場合だけ変更する。
+// 他のモデレーターが先に変更していた場合はエラーを返す。同じステータスへの変更は何もしない。
+func (c *Comment) Moderate(tx *gorm.DB, status CommentStatus, moderatorID uint, reason string) error {
+    if c.Status == status {
+        return nil
+    }
+    changed, err := ModerateComments(tx, []uint{c.ID}, c.Status, status, moderatorID, reason)
+    if err != nil {
+        return err
+    }
+    if len(changed) == 0 {
+        return fmt.Errorf("コメントのステータスが既に変更されています: ID=%d", c.ID)
+    }
+    c.Status = status
+    return nil
 }
 
 // CountReplies 返信数をカウント
@@ -196,6 +265,8 @@ func (c *Comment) CountReplies(tx *gorm.DB) (int64, error) {
 }
 
 // GetDepth コメントの階層の深さを取得
+//
+// 親を1段ずつ読み込む。1クエリで求める場合は CommentRepository.GetDepth を使う。
 func (c *Comment) GetDepth(tx *gorm.DB) (int, error) {
     depth := 0
     currentComment := c
@@ -217,88 +288,34 @@ func (c *Comment) GetDepth(tx *gorm.DB) (int, error) {
     return depth, nil
 }
 
-// DetectSpamKeywords スパムキーワード検出
-func (c *Comment) DetectSpamKeywords() bool {
-    spamKeywords := []string{
-        "viagra", "casino", "lottery", "winner", "congratulations",
-        "click here", "make money", "work from home", "free money",
-        // 日本語のスパムキーワード
-        "出会い", "副業", "稼げる", "無料", "限定",
-    }
-    
-    lowerBody := strings.ToLower(c.Body)
-    
-    for _, keyword := range spamKeywords {
-        if strings.Contains(lowerBody, keyword) {
-            return true
-        }
-    }
-    
-    return false
-}
-
 // IsFromSuspiciousIP 疑わしいIPからのコメントか判定
+//
+// モデレーション設定の IP 拒否リスト（CIDR）に一致するかで判定する。
+// プライベートアドレスも含め、リストにないアドレスや ip_reputation が無効な場合は false。
 func (c *Comment) IsFromSuspiciousIP() bool {
-    // 簡易的な実装例
-    // 実際にはIPレピュテーションDBやブラックリストと照合
-    suspiciousPatterns := []string{
-        "192.168.", // プライベートIP（実際の運用では除外）
-        "10.",      // プライベートIP
-        "172.",     // プライベートIP
-    }
-    
-    for _, pattern := range suspiciousPatterns {
-        if strings.HasPrefix(c.IPAddress, pattern) {
-            return true
-        }
-    }
-    
-    return false
-}
-
-// CalculateSpamScore スパムスコア計算
-func (c *Comment) CalculateSpamScore() float64 {
-    score := 0.0
-    
-    // キーワードベースのスコア
-    if c.DetectSpamKeywords() {
-        score += 0.5
-    }
-    
-    // IP ベースのスコア
-    if c.IsFromSuspiciousIP() {
-        score += 0.3
-    }
-    
-    // 長さベースのスコア（極端に短い、または長い）
-    bodyLength := len(c.Body)
-    if bodyLength < 10 || bodyLength > 1500 {
-        score += 0.2
-    }
-    
-    // URL の数（多すぎる場合）
-    urlCount := strings.Count(strings.ToLower(c.Body), "http")
-    if urlCount > 2 {
-        score += float64(urlCount) * 0.1
+    ip := moderation.Default().IPReputation()
+    if ip == nil {
+        return false
     }
-    
-    // スコアは0.0-1.0の範囲に正規化
-    if score > 1.0 {
-        score = 1.0
-    }
-    
-    return score
+    return ip.Check(c.IPAddress) == ipreputation.Deny
 }
 
 // AutoModerate 自動モデレーション
-func (c *Comment) AutoModerate() CommentStatus {
-    spamScore := c.CalculateSpamScore()
-    
-    if spamScore >= 0.7 {
-        return CommentStatusSpam
-    } else if spamScore >= 0.4 {
-        return CommentStatusPending // 手動確認が必要
+//
+// 既定のモデレーションパイプラインで判定し、結果を c.Moderation に残して返す。
+// 本文はエスケープ前のものを渡すこと（作成前に呼ぶ）。tx が nil の場合は本文だけで判定する。
+func (c *Comment) AutoModerate(tx *gorm.DB) (*moderation.Result, error) {
+    result, err := moderation.Default().Evaluate(tx, moderation.Input{
+        UserID:      c.UserID,
+        PostID:      c.PostID,
+        Body:        c.Body,
+        IPAddress:   c.IPAddress,
+        ContentHash: c.ContentHash,
+        CreatedAt:   c.CreatedAt,
+    })
+    if err != nil {
+        return nil, err
     }
-    
-    return CommentStatusApproved // 自動承認
-}
\ No newline at end of file
+    c.Moderation = result
+    return result, nil
+}
@@ -0,0 +1,42 @@
+// internal/models/comment_rollup.go
+package models
+
+import (
+	"time"
+)
+
+// ActivitySource UserCommentActivity の集計方法
+type ActivitySource string
+
+const (
+	// ActivityLive comments テーブルをその場で集計（常に最新だが、期間内の全コメントを読む）
+	ActivityLive ActivitySource = "live"
+	// ActivityRollup 日別の事前集計（comment_daily_rollups）を合算（最後に更新した時点の値。期間の端の日の途中の部分は comments から集計）
+	ActivityRollup ActivitySource = "rollup"
+)
+
+// CommentDailyRollup ユーザー別・日別のコメント集計
+//
+// UserCommentActivity を日数 × ユーザー数の行から求めるための事前集計。
+// commentRepository.RefreshActivityRollup で日ごとに作り直す（コメントの作成時には更新しない）。
+// 集計の再作成を軽くするため外部キーは付けない（削除されたユーザーの行は検索時の JOIN で除かれる）。
+type CommentDailyRollup struct {
+	UserID        uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Day           time.Time `gorm:"primaryKey;type:date;index:idx_comment_rollup_day" json:"day"`
+	CommentCount  int64     `gorm:"not null" json:"comment_count"`
+	SpamCount     int64     `gorm:"not null" json:"spam_count"`
+	TotalLength   int64     `gorm:"not null" json:"total_length"` // 本文の文字数の合計（平均の計算用）
+	LastCommentAt time.Time `gorm:"not null" json:"last_comment_at"`
+	RefreshedAt   time.Time `gorm:"not null" json:"refreshed_at"`
+}
+
+// TableName テーブル名を明示的に指定
+func (CommentDailyRollup) TableName() string {
+	return "comment_daily_rollups"
+}
+
+// StartOfDay t の日の始まり（t のタイムゾーン）
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
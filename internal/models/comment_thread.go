@@ -0,0 +1,97 @@
+// internal/models/comment_thread.go
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThreadStrategy 返信ツリー（スレッド）の読み込み方法
+type ThreadStrategy string
+
+const (
+	// ThreadAdjacency parent_id を1段ずつたどる（段数だけクエリを発行する）
+	ThreadAdjacency ThreadStrategy = "adjacency"
+	// ThreadRecursive parent_id を WITH RECURSIVE でたどる（1クエリ）
+	ThreadRecursive ThreadStrategy = "recursive"
+	// ThreadPath マテリアライズドパス（comments.path）の前方一致（1クエリ。path の保守が必要）
+	ThreadPath ThreadStrategy = "path"
+)
+
+// ThreadStrategies 全ての読み込み方法
+var ThreadStrategies = []ThreadStrategy{ThreadAdjacency, ThreadRecursive, ThreadPath}
+
+// MaxThreadDepth スレッドをたどる最大の段数（親子関係が循環していても止まるようにする）
+//
+// path を持てる段数と同じにし、どの読み込み方法でも同じ範囲の返信を返す。
+// これより深い返信も作成はできるが、path は空のままでスレッドには含まれない。
+const MaxThreadDepth = CommentPathMaxDepth
+
+// ErrThreadTooDeep コメントの段数が MaxThreadDepth を超えている
+var ErrThreadTooDeep = fmt.Errorf("返信の階層が深すぎます（%d段まで）", MaxThreadDepth)
+
+// マテリアライズドパスの形式
+//
+// ルートから自分までの ID を CommentPathDigits 桁の0埋めにし、それぞれの後ろに "/" を付けて連結する
+// （例: "0000000012/0000000345/"）。固定長なので path の順に並べると深さ優先の順になり、
+// 子孫は path の前方一致で求められる。
+const (
+	CommentPathDigits    = 10
+	commentPathSegment   = CommentPathDigits + 1
+	CommentPathMaxLength = 255
+	// CommentPathMaxDepth path を持てる最も深い返信の段数（ルートは 0）
+	CommentPathMaxDepth = CommentPathMaxLength/commentPathSegment - 1
+)
+
+// ThreadComment スレッド内のコメントとルートからの段数
+type ThreadComment struct {
+	Comment
+	Depth int `json:"depth"`
+}
+
+// CommentPath 親の path（ルートコメントは空）と ID から path を作る
+func CommentPath(parentPath string, id uint) (string, error) {
+	path := fmt.Sprintf("%s%0*d/", parentPath, CommentPathDigits, id)
+	if len(path) > CommentPathMaxLength {
+		return "", fmt.Errorf("path を作れません: ID=%d: %w", id, ErrThreadTooDeep)
+	}
+	return path, nil
+}
+
+// CommentPathDepth path から求めた段数（ルートは 0。path が空なら -1）
+func CommentPathDepth(path string) int {
+	return strings.Count(path, "/") - 1
+}
+
+// commentPathSQL SQL で path の1段分を作る式（CommentPath と同じ形式）
+func commentPathSQL(column string) string {
+	return fmt.Sprintf("LPAD(%s, %d, '0'), '/'", column, CommentPathDigits)
+}
+
+// RebuildCommentPathsSQL ルートコメントから WITH RECURSIVE で path を作り直す UPDATE 文
+//
+// rootCondition はルートコメント（別名 r）の条件（例: "r.id BETWEEN ? AND ?"）。
+// 返信はルートと同じ文で更新される。CommentPathMaxDepth より深い返信は更新しない。
+func RebuildCommentPathsSQL(rootCondition string) string {
+	return fmt.Sprintf(`UPDATE comments c JOIN (
+		WITH RECURSIVE tree (id, path, depth) AS (
+			SELECT r.id, CAST(CONCAT(%s) AS CHAR(%d)), 0 FROM comments r
+			WHERE r.parent_id IS NULL AND %s
+			UNION ALL
+			SELECT child.id, CONCAT(t.path, %s), t.depth + 1
+			FROM comments child JOIN tree t ON child.parent_id = t.id
+			WHERE t.depth < %d
+		)
+		SELECT id, path FROM tree
+	) p ON p.id = c.id SET c.path = p.path WHERE c.path <> p.path`,
+		commentPathSQL("r.id"), CommentPathMaxLength, rootCondition,
+		commentPathSQL("child.id"), CommentPathMaxDepth)
+}
+
+// CommentPathMismatchSQL 親の path と一致しない path を持つコメント（別名 c、親は p）の条件
+//
+// path が空のコメント（作り直す前の行）は対象にしない。
+func CommentPathMismatchSQL() string {
+	return fmt.Sprintf("c.path <> '' AND c.path <> CONCAT(IF(c.parent_id IS NULL, '', COALESCE(p.path, '')), %s)",
+		commentPathSQL("c.id"))
+}
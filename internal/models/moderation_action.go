@@ -0,0 +1,102 @@
+// internal/models/moderation_action.go
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockForUpdate 変更対象の行を SELECT ... FOR UPDATE でロックする
+var lockForUpdate = clause.Locking{Strength: "UPDATE"}
+
+// ModerationAction コメントのステータス変更の監査ログ
+//
+// 誰が・いつ・なぜステータスを変えたかを残す。ステータスが実際に変わった場合だけ1行追加する。
+// モデレーターのユーザーが削除されてもログは残す（ModeratorID は NULL になる）。
+type ModerationAction struct {
+	ID          uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	CommentID   uint          `gorm:"not null;index:idx_moderation_action_comment,priority:1" json:"comment_id"`
+	ModeratorID *uint         `gorm:"null;index:idx_moderation_action_moderator,priority:1" json:"moderator_id,omitempty"`
+	FromStatus  CommentStatus `gorm:"size:20;not null" json:"from_status"`
+	ToStatus    CommentStatus `gorm:"size:20;not null" json:"to_status"`
+	Reason      string        `gorm:"size:500" json:"reason,omitempty"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime;index:idx_moderation_action_comment,priority:2;index:idx_moderation_action_moderator,priority:2;index:idx_moderation_action_created_at" json:"created_at"`
+
+	// リレーション
+	Comment   Comment `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"-" validate:"-"`
+	Moderator *User   `gorm:"foreignKey:ModeratorID;constraint:OnDelete:SET NULL" json:"moderator,omitempty" validate:"-"`
+}
+
+// TableName テーブル名を明示的に指定
+func (ModerationAction) TableName() string {
+	return "moderation_actions"
+}
+
+// ModeratorActivity モデレーター別の操作件数
+type ModeratorActivity struct {
+	ModeratorID   uint      `json:"moderator_id"`
+	ModeratorName string    `json:"moderator_name"`
+	ActionCount   int64     `json:"action_count"`
+	ApprovedCount int64     `json:"approved_count"`
+	SpamCount     int64     `json:"spam_count"`
+	DeletedCount  int64     `json:"deleted_count"`
+	LastActionAt  time.Time `json:"last_action_at"`
+}
+
+// newModerationAction ステータス変更の監査ログを作る
+func newModerationAction(commentID uint, from, to CommentStatus, moderatorID uint, reason string) ModerationAction {
+	action := ModerationAction{CommentID: commentID, FromStatus: from, ToStatus: to, Reason: reason}
+	if moderatorID != 0 {
+		action.ModeratorID = &moderatorID
+	}
+	return action
+}
+
+// ModerateComments コメントのステータスをまとめて変更し、変更した行の監査ログを残す
+//
+// from のステータスのコメントだけを変更する（他のモデレーターが先に変更した行は飛ばす）。
+// 対象の行をロックしてから UPDATE と監査ログの INSERT を同じトランザクションで行い、
+// 実際に変更したコメントの ID を返す。
+func ModerateComments(tx *gorm.DB, ids []uint, from, to CommentStatus, moderatorID uint, reason string) ([]uint, error) {
+	if len(ids) == 0 || from == to {
+		return nil, nil
+	}
+
+	var changed []uint
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Comment{}).
+			Clauses(lockForUpdate).
+			Where("id IN ? AND status = ?", ids, from).
+			Order("id").
+			Pluck("id", &changed).Error
+		if err != nil {
+			return fmt.Errorf("モデレーション対象の取得エラー: %w", err)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		err = tx.Model(&Comment{}).
+			Where("id IN ?", changed).
+			Update("status", to).Error
+		if err != nil {
+			return fmt.Errorf("コメントのステータス更新エラー: %w", err)
+		}
+
+		actions := make([]ModerationAction, len(changed))
+		for i, id := range changed {
+			actions[i] = newModerationAction(id, from, to, moderatorID, reason)
+		}
+		if err := tx.Create(&actions).Error; err != nil {
+			return fmt.Errorf("監査ログの記録エラー: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
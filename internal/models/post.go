@@ -2,12 +2,17 @@
 package models
 
 import (
-	"strings"
 	"time"
 
+	"go-db-performance-study/internal/slug"
+	"go-db-performance-study/internal/textutil"
+
 	"gorm.io/gorm"
 )
 
+// PostExcerptLength 抜粋の最大文字数（省略記号を含む）
+const PostExcerptLength = 200
+
 // PostStatus 投稿ステータス
 type PostStatus string
 
@@ -54,6 +59,7 @@ type PostForCreate struct {
 type PostForUpdate struct {
 	Title  *string     `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
 	Body   *string     `json:"body,omitempty" validate:"omitempty,min=1"`
+	Slug   *string     `json:"slug,omitempty" validate:"omitempty,min=1,max=255"`
 	Status *PostStatus `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
 	TagIDs []uint      `json:"tag_ids"`
 }
@@ -88,9 +94,15 @@ type PostSummary struct {
 }
 
 // BeforeCreate 作成前処理（スラッグ生成）
+//
+// slug が未指定の場合はタイトルから作り、既存の投稿と重複すれば -2, -3 と連番を付ける。
 func (p *Post) BeforeCreate(tx *gorm.DB) error {
 	if p.Slug == "" {
-		p.Slug = p.generateSlug()
+		s, err := slug.Posts.Unique(tx, slug.Posts.Make(p.Title), 0)
+		if err != nil {
+			return err
+		}
+		p.Slug = s
 	}
 	if p.Excerpt == "" {
 		p.Excerpt = p.generateExcerpt()
@@ -99,27 +111,37 @@ func (p *Post) BeforeCreate(tx *gorm.DB) error {
 }
 
 // BeforeUpdate 更新前処理
+//
+// slug を変更した場合は既存の投稿と重複しないよう連番を付け、変更前の slug を履歴に残す。
+// タイトルを変更しても slug は変えない（公開済みの URL を保つため）。
 func (p *Post) BeforeUpdate(tx *gorm.DB) error {
-	if p.Excerpt == "" {
+	if tx.Statement.Changed("slug") {
+		s, err := slug.Posts.Unique(tx, slug.Posts.Make(updatedString(tx, "slug", p.Slug)), p.ID)
+		if err != nil {
+			return err
+		}
+		if err := RecordSlugChange(tx, slug.Posts, p.ID, p.Slug, s); err != nil {
+			return err
+		}
+		tx.Statement.SetColumn("slug", s)
+	}
+	// 本文を変更した場合は抜粋も作り直す
+	if tx.Statement.Changed("body") {
+		tx.Statement.SetColumn("excerpt", PostExcerpt(updatedString(tx, "body", p.Body)))
+	} else if p.Excerpt == "" {
 		p.Excerpt = p.generateExcerpt()
 	}
 	return nil
 }
 
-// generateSlug スラッグ生成
-func (p *Post) generateSlug() string {
-	slug := strings.ToLower(p.Title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	// 実際には、より高度なスラッグ生成ロジックを実装
-	return slug
-}
-
 // generateExcerpt 抜粋生成
 func (p *Post) generateExcerpt() string {
-	if len(p.Body) <= 200 {
-		return p.Body
-	}
-	return p.Body[:200] + "..."
+	return PostExcerpt(p.Body)
+}
+
+// PostExcerpt 本文（Markdown・HTML）から記法を除いた抜粋を作る（データ生成でも使う）
+func PostExcerpt(body string) string {
+	return textutil.Excerpt(textutil.PlainText(body), PostExcerptLength)
 }
 
 // Validate バリデーション実行
@@ -0,0 +1,65 @@
+// internal/models/slug_history.go
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/slug"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SlugHistory 投稿・タグの以前の slug
+//
+// 名前の変更などで slug が変わっても古い URL から辿れるよう、変更前の slug を残す。
+// EntityType は slug.Scope のテーブル名（posts / tags）。
+type SlugHistory struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EntityType string    `gorm:"size:20;not null;uniqueIndex:idx_slug_history_slug,priority:1;index:idx_slug_history_entity,priority:1" json:"entity_type"`
+	Slug       string    `gorm:"size:255;not null;uniqueIndex:idx_slug_history_slug,priority:2" json:"slug"`
+	EntityID   uint      `gorm:"not null;index:idx_slug_history_entity,priority:2" json:"entity_id"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index:idx_slug_history_created_at" json:"created_at"`
+}
+
+// TableName テーブル名を明示的に指定
+func (SlugHistory) TableName() string {
+	return "slug_histories"
+}
+
+// RecordSlugChange slug の変更を履歴に記録する（BeforeUpdate から同じトランザクションで呼ぶ）
+//
+// 同じ旧 slug が既に履歴にあれば、最後に使っていた行を指すよう付け替える。
+// 新しい slug は現在使われているため、履歴にあれば取り除く（元の slug に戻した場合など）。
+func RecordSlugChange(tx *gorm.DB, scope slug.Scope, id uint, oldSlug, newSlug string) error {
+	if id == 0 || oldSlug == "" || strings.EqualFold(oldSlug, newSlug) {
+		return nil
+	}
+	db := tx.Session(&gorm.Session{NewDB: true})
+
+	history := SlugHistory{EntityType: scope.Table, Slug: oldSlug, EntityID: id}
+	err := db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+	}).Create(&history).Error
+	if err != nil {
+		return fmt.Errorf("slug 履歴の記録エラー: %w", err)
+	}
+
+	err = db.Where("entity_type = ? AND slug = ?", scope.Table, newSlug).Delete(&SlugHistory{}).Error
+	if err != nil {
+		return fmt.Errorf("slug 履歴の削除エラー: %w", err)
+	}
+	return nil
+}
+
+// updatedString 更新後の列の値（Updates(map) の値があればそれ、なければ現在の値）
+func updatedString(tx *gorm.DB, column, current string) string {
+	if m, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+		if v, ok := m[column].(string); ok {
+			return v
+		}
+	}
+	return current
+}
@@ -1,11 +1,11 @@
 package models
 
 import (
-	"fmt"
-	"regexp"
 	"strings"
 	"time"
 
+	"go-db-performance-study/internal/slug"
+
 	"gorm.io/gorm"
 )
 
@@ -82,9 +82,13 @@ type TagPopularity struct {
 
 // BeforeCreate 作成前処理
 func (t *Tag) BeforeCreate(tx *gorm.DB) error {
-    // スラッグ自動生成
+    // スラッグ自動生成（既存のタグと重複すれば連番を付ける）
     if t.Slug == "" {
-        t.Slug = t.GenerateSlug()
+        s, err := slug.Tags.Unique(tx, t.GenerateSlug(), 0)
+        if err != nil {
+            return err
+        }
+        t.Slug = s
     }
     
     // デフォルトカラー設定
@@ -96,37 +100,32 @@ func (t *Tag) BeforeCreate(tx *gorm.DB) error {
 }
 
 // BeforeUpdate 更新前処理
+//
+// 名前が変わると slug も作り直し、変更前の slug を履歴に残す。
+// 履歴には変更前の slug が必要なため、読み込んだタグに対して Model(&tag).Updates(...) で更新すること。
 func (t *Tag) BeforeUpdate(tx *gorm.DB) error {
     // 名前が変更された場合、スラッグも更新
     if tx.Statement.Changed("name") {
-        t.Slug = t.GenerateSlug()
+        name := updatedString(tx, "name", t.Name)
+        s, err := slug.Tags.Unique(tx, slug.Tags.Make(name), t.ID)
+        if err != nil {
+            return err
+        }
+        if err := RecordSlugChange(tx, slug.Tags, t.ID, t.Slug, s); err != nil {
+            return err
+        }
+        // Updates(map) で更新する場合も slug が書き込まれるよう SetColumn を使う
+        tx.Statement.SetColumn("slug", s)
     }
     
     return nil
 }
 
-// GenerateSlug スラッグ生成
+// GenerateSlug 名前からスラッグを生成（かなはローマ字にし、漢字などはそのまま残す）
+//
+// 重複の確認はしない。作成・更新時は BeforeCreate / BeforeUpdate で連番が付く。
 func (t *Tag) GenerateSlug() string {
-    // 日本語・英語対応のスラッグ生成
-    slug := strings.ToLower(t.Name)
-    
-    // 特殊文字を削除・置換
-    reg := regexp.MustCompile(`[^\w\-]`)
-    slug = reg.ReplaceAllString(slug, "-")
-    
-    // 連続するハイフンを単一に
-    reg = regexp.MustCompile(`-+`)
-    slug = reg.ReplaceAllString(slug, "-")
-    
-    // 前後のハイフンを削除
-    slug = strings.Trim(slug, "-")
-    
-    // 空の場合はランダムな文字列
-    if slug == "" {
-        slug = fmt.Sprintf("tag-%d", time.Now().Unix())
-    }
-    
-    return slug
+    return slug.Tags.Make(t.Name)
 }
 
 // Validate バリデーション実行
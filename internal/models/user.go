@@ -2,22 +2,27 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"go-db-performance-study/internal/password"
+
 	"gorm.io/gorm"
 )
 
 // User ユーザーモデル
 type User struct {
-	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name            string     `gorm:"size:255;not null;index:idx_user_name" json:"name" validate:"required,min=1,max=255"`
-	Email           string     `gorm:"size:255;uniqueIndex:idx_user_email;not null" json:"email" validate:"required,email,max=255"`
-	EmailVerifiedAt *time.Time `gorm:"null" json:"email_verified_at"`
-	Password        string     `gorm:"size:255;not null" json:"-" validate:"required,min=6"`
-	RememberToken   *string    `gorm:"size:100;null;index:idx_user_remember_token" json:"-"`
-	CreatedAt       time.Time  `gorm:"autoCreateTime;index:idx_user_created_at" json:"created_at"`
-	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                       string     `gorm:"size:255;not null;index:idx_user_name" json:"name" validate:"required,min=1,max=255"`
+	Email                      string     `gorm:"size:255;uniqueIndex:idx_user_email;not null" json:"email" validate:"required,email,max=255"`
+	EmailVerifiedAt            *time.Time `gorm:"null" json:"email_verified_at"`
+	Password                   string     `gorm:"size:255;not null" json:"-" validate:"required,min=6"`
+	RememberToken              *string    `gorm:"size:100;null;index:idx_user_remember_token" json:"-"` // トークンの SHA-256（平文は保存しない）
+	RememberTokenExpiresAt     *time.Time `gorm:"null" json:"-"`
+	EmailVerificationToken     *string    `gorm:"size:64;null;index:idx_user_verification_token" json:"-"` // トークンの SHA-256（平文は保存しない）
+	EmailVerificationExpiresAt *time.Time `gorm:"null" json:"-"`
+	CreatedAt                  time.Time  `gorm:"autoCreateTime;index:idx_user_created_at" json:"created_at"`
+	UpdatedAt                  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// リレーション（パフォーマンス最適化）
 	Posts    []Post    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"posts,omitempty" validate:"-"`
@@ -61,21 +66,47 @@ type UserStats struct {
 }
 
 // BeforeCreate 作成前処理（パスワードハッシュ化）
+//
+// 入力が何であっても password.Default() の方式でハッシュ化する。
+// ハッシュ化済みの行を挿入する場合（データ生成）は Session{SkipHooks: true} でこのフックを飛ばす。
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		hashedPassword, err := password.Default().Hash(u.Password)
 		if err != nil {
 			return err
 		}
-		u.Password = string(hashedPassword)
+		u.Password = hashedPassword
 	}
 	return nil
 }
 
 // CheckPassword パスワード確認
-func (u *User) CheckPassword(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
-	return err == nil
+//
+// 一致したハッシュが password.Default() と異なる方式・パラメータの場合は、今の設定で作り直して保存する。
+// 保存に失敗した場合も一致したことは true で返す（err は保存のエラー）。
+func (u *User) CheckPassword(tx *gorm.DB, plain string) (bool, error) {
+	ok, err := password.Verify(u.Password, plain)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	hasher := password.Default()
+	if !hasher.NeedsRehash(u.Password) {
+		return true, nil
+	}
+	rehashed, err := hasher.Hash(plain)
+	if err != nil {
+		return true, err
+	}
+	// 同時にパスワードが変更されていれば上書きしない
+	err = tx.Session(&gorm.Session{NewDB: true}).Model(&User{}).
+		Where("id = ? AND password = ?", u.ID, u.Password).
+		UpdateColumn("password", rehashed).Error
+	if err != nil {
+		return true, fmt.Errorf("パスワードの再ハッシュの保存エラー: %w", err)
+	}
+	u.Password = rehashed
+	return true, nil
 }
 
 // Validate バリデーション実行
@@ -0,0 +1,192 @@
+// internal/moderation/config.go
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	appconfig "go-db-performance-study/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile モデレーション設定ファイルの既定のパス
+const DefaultFile = "configs/moderation.yaml"
+
+// Config モデレーションの規則ごとの設定と判定のしきい値
+//
+// 各規則は発火したときにスコアを加点し、合計（1.0 が上限）をしきい値と比べて判定する。
+type Config struct {
+	Thresholds Thresholds       `yaml:"thresholds"`
+	Keywords   KeywordsConfig   `yaml:"keywords"`
+	URLs       URLsConfig       `yaml:"urls"`
+	Length     LengthConfig     `yaml:"length"`
+	Rate       RateConfig       `yaml:"rate"`
+	Duplicate  DuplicateConfig  `yaml:"duplicate"`
+	Reputation ReputationConfig `yaml:"reputation"`
+	IP         IPConfig         `yaml:"ip_reputation"`
+}
+
+// Thresholds 判定のしきい値（スコアがこれ以上ならその判定）
+type Thresholds struct {
+	Spam    float64 `yaml:"spam"`
+	Pending float64 `yaml:"pending"`
+}
+
+// KeywordsConfig スパムキーワードの規則
+type KeywordsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Files キーワードの一覧ファイル（1行1語、# 以降はコメント）。空の場合は組み込みの一覧
+	Files  []string `yaml:"files"`
+	Weight float64  `yaml:"weight"`
+}
+
+// URLsConfig 本文中の URL の数の規則
+type URLsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Max これより多い URL を含む場合に発火する
+	Max int `yaml:"max"`
+	// WeightPerURL URL 1件あたりの加点
+	WeightPerURL float64 `yaml:"weight_per_url"`
+}
+
+// LengthConfig 本文の長さ（文字数）の規則
+type LengthConfig struct {
+	Enabled  bool    `yaml:"enabled"`
+	MinRunes int     `yaml:"min_runes"`
+	MaxRunes int     `yaml:"max_runes"`
+	Weight   float64 `yaml:"weight"`
+}
+
+// RateConfig 同じ IP・ユーザーからの投稿頻度の規則
+type RateConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"`
+	// MaxPerIP / MaxPerUser Window 内に許すコメント数（0 の場合は確認しない）
+	MaxPerIP   int     `yaml:"max_per_ip"`
+	MaxPerUser int     `yaml:"max_per_user"`
+	Weight     float64 `yaml:"weight"`
+}
+
+// DuplicateConfig 同じ内容のコメントの規則（正規化した本文のハッシュで比較）
+type DuplicateConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Window この期間内のコメントと比べる（0 の場合は期間を限らない）
+	Window time.Duration `yaml:"window"`
+	// SameUser true の場合は同じユーザーのコメントだけと比べる
+	SameUser bool    `yaml:"same_user"`
+	Weight   float64 `yaml:"weight"`
+}
+
+// ReputationConfig 過去のコメントのスパム率の規則（加点は Weight × スパム率）
+type ReputationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Recent 直近何件のコメントでスパム率を計算するか
+	Recent int `yaml:"recent"`
+	// MinComments これより少ない場合は判定しない
+	MinComments int     `yaml:"min_comments"`
+	Weight      float64 `yaml:"weight"`
+}
+
+// IPConfig 送信元 IP アドレスの許可・拒否リストの規則（拒否リストに一致すると加点）
+type IPConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	AllowFiles []string `yaml:"allow_files"`
+	DenyFiles  []string `yaml:"deny_files"`
+	// ReloadInterval この間隔でファイルの変更を確認して読み込み直す（0 の場合は読み込み直さない）
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+	Weight         float64       `yaml:"weight"`
+}
+
+// DefaultConfig 既定の設定（設定ファイルで書いた項目だけを上書きする）
+func DefaultConfig() Config {
+	return Config{
+		Thresholds: Thresholds{Spam: 0.7, Pending: 0.4},
+		Keywords:   KeywordsConfig{Enabled: true, Weight: 0.5},
+		URLs:       URLsConfig{Enabled: true, Max: 2, WeightPerURL: 0.1},
+		Length:     LengthConfig{Enabled: true, MinRunes: 3, MaxRunes: 1500, Weight: 0.2},
+		Rate:       RateConfig{Enabled: true, Window: 10 * time.Minute, MaxPerIP: 20, MaxPerUser: 10, Weight: 0.4},
+		Duplicate:  DuplicateConfig{Enabled: true, Window: 24 * time.Hour, Weight: 0.4},
+		Reputation: ReputationConfig{Enabled: true, Recent: 100, MinComments: 5, Weight: 0.6},
+		IP:         IPConfig{Weight: 0.5}, // リストのファイルは設定ファイルで指定する
+	}
+}
+
+// LoadConfig 設定ファイルを読み込む
+//
+// 設定ファイルと、その中に書いたキーワード・IP リストのファイルの相対パスは、
+// database.Connect の設定ファイルと同じく config.ResolvePath で解決する。
+// 既定のパスのファイルが存在しない場合は DefaultConfig を返す。
+func LoadConfig(path string) (Config, error) {
+	file := appconfig.ResolvePath(path)
+	config := DefaultConfig()
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && path == DefaultFile {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("モデレーション設定の読み込みエラー: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("%s: YAML解析エラー: %w", file, err)
+	}
+	for _, files := range [][]string{config.Keywords.Files, config.IP.AllowFiles, config.IP.DenyFiles} {
+		for i := range files {
+			files[i] = appconfig.ResolvePath(files[i])
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("%s: %w", file, err)
+	}
+	return config, nil
+}
+
+// Validate 設定値の検証
+func (c Config) Validate() error {
+	t := c.Thresholds
+	if t.Pending < 0 || t.Pending > t.Spam || t.Spam > 1 {
+		return fmt.Errorf("しきい値は 0 <= pending <= spam <= 1 で指定してください: pending=%v spam=%v", t.Pending, t.Spam)
+	}
+	weights := []struct {
+		name   string
+		weight float64
+	}{
+		{"keywords.weight", c.Keywords.Weight},
+		{"urls.weight_per_url", c.URLs.WeightPerURL},
+		{"length.weight", c.Length.Weight},
+		{"rate.weight", c.Rate.Weight},
+		{"duplicate.weight", c.Duplicate.Weight},
+		{"reputation.weight", c.Reputation.Weight},
+		{"ip_reputation.weight", c.IP.Weight},
+	}
+	for _, w := range weights {
+		if w.weight < 0 {
+			return fmt.Errorf("%s は 0 以上で指定してください: %v", w.name, w.weight)
+		}
+	}
+	if c.URLs.Max < 0 {
+		return fmt.Errorf("urls.max は 0 以上で指定してください: %d", c.URLs.Max)
+	}
+	if c.Length.MaxRunes > 0 && c.Length.MinRunes > c.Length.MaxRunes {
+		return fmt.Errorf("length.min_runes (%d) が max_runes (%d) より大きくなっています", c.Length.MinRunes, c.Length.MaxRunes)
+	}
+	if c.Rate.Enabled && c.Rate.Window <= 0 {
+		return fmt.Errorf("rate.window は正の期間で指定してください: %v", c.Rate.Window)
+	}
+	if c.Duplicate.Window < 0 {
+		return fmt.Errorf("duplicate.window は 0 以上で指定してください: %v", c.Duplicate.Window)
+	}
+	if c.Reputation.Enabled && c.Reputation.Recent <= 0 {
+		return fmt.Errorf("reputation.recent は 1 以上で指定してください: %d", c.Reputation.Recent)
+	}
+	if c.IP.Enabled && len(c.IP.AllowFiles)+len(c.IP.DenyFiles) == 0 {
+		return fmt.Errorf("ip_reputation: allow_files か deny_files を指定してください")
+	}
+	if c.IP.ReloadInterval < 0 {
+		return fmt.Errorf("ip_reputation.reload_interval は 0 以上で指定してください: %v", c.IP.ReloadInterval)
+	}
+	return nil
+}
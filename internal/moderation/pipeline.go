@@ -0,0 +1,222 @@
+// internal/moderation/pipeline.go
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"go-db-performance-study/internal/ipreputation"
+
+	"gorm.io/gorm"
+)
+
+// Status 判定結果（models.CommentStatus と同じ値）
+type Status string
+
+const (
+	StatusApproved Status = "approved"
+	StatusPending  Status = "pending"
+	StatusSpam     Status = "spam"
+)
+
+// spamStatus 過去のコメントのスパム判定に使う comments.status の値
+const spamStatus = string(StatusSpam)
+
+// Input 判定するコメント
+type Input struct {
+	UserID    uint
+	PostID    uint
+	Body      string // エスケープ前の本文
+	IPAddress string
+	// ContentHash 本文のハッシュ（空の場合は Body から計算する）
+	ContentHash string
+	// CreatedAt 頻度・重複を数える期間の基準（ゼロ値の場合は現在時刻）
+	CreatedAt time.Time
+
+	body string // 正規化した本文（キャッシュ）
+}
+
+// normalized 正規化した本文
+func (in *Input) normalized() string {
+	if in.body == "" {
+		in.body = normalize(in.Body)
+	}
+	return in.body
+}
+
+// hash 本文のハッシュ
+func (in *Input) hash() string {
+	if in.ContentHash == "" {
+		in.ContentHash = ContentHash(in.Body)
+	}
+	return in.ContentHash
+}
+
+// Hit 発火した規則
+type Hit struct {
+	Rule   string  `json:"rule"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Result 判定結果と、その理由になった規則
+type Result struct {
+	Status Status  `json:"status"`
+	Score  float64 `json:"score"` // 発火した規則の加点の合計（1.0 が上限）
+	Hits   []Hit   `json:"hits,omitempty"`
+}
+
+// Explain 判定の説明（例: "spam (0.80): keywords +0.50 [casino], urls +0.30 [URL 3件（上限 2件）]"）
+func (r *Result) Explain() string {
+	if len(r.Hits) == 0 {
+		return fmt.Sprintf("%s (%.2f): 発火した規則なし", r.Status, r.Score)
+	}
+	parts := make([]string, len(r.Hits))
+	for i, h := range r.Hits {
+		parts[i] = fmt.Sprintf("%s +%.2f [%s]", h.Rule, h.Score, h.Reason)
+	}
+	return fmt.Sprintf("%s (%.2f): %s", r.Status, r.Score, strings.Join(parts, ", "))
+}
+
+// Pipeline 規則を順に評価し、加点の合計から判定する
+//
+// 規則は読み取り専用なので、複数のゴルーチンから同時に使える。
+type Pipeline struct {
+	thresholds Thresholds
+	rules      []Rule
+	ip         *ipreputation.Reputation
+	stop       context.CancelFunc // IP リストの再読み込みを止める
+}
+
+// New 設定から有効な規則を組み立てる
+func New(config Config) (*Pipeline, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	p := &Pipeline{thresholds: config.Thresholds}
+	if config.Keywords.Enabled {
+		r, err := newKeywordsRule(config.Keywords)
+		if err != nil {
+			return nil, err
+		}
+		p.rules = append(p.rules, r)
+	}
+	if config.URLs.Enabled {
+		p.rules = append(p.rules, &urlsRule{max: config.URLs.Max, weightPerURL: config.URLs.WeightPerURL})
+	}
+	if config.Length.Enabled {
+		p.rules = append(p.rules, &lengthRule{min: config.Length.MinRunes, max: config.Length.MaxRunes, weight: config.Length.Weight})
+	}
+	if config.Rate.Enabled {
+		p.rules = append(p.rules, &rateRule{config: config.Rate})
+	}
+	if config.Duplicate.Enabled {
+		p.rules = append(p.rules, &duplicateRule{config: config.Duplicate})
+	}
+	if config.Reputation.Enabled {
+		p.rules = append(p.rules, &reputationRule{config: config.Reputation})
+	}
+	if config.IP.Enabled {
+		ip, err := ipreputation.Load(ipreputation.Config{AllowFiles: config.IP.AllowFiles, DenyFiles: config.IP.DenyFiles})
+		if err != nil {
+			return nil, err
+		}
+		p.ip = ip
+		p.rules = append(p.rules, &ipRule{reputation: ip, weight: config.IP.Weight})
+		if config.IP.ReloadInterval > 0 {
+			ctx, cancel := context.WithCancel(context.Background())
+			p.stop = cancel
+			go ip.Watch(ctx, config.IP.ReloadInterval)
+		}
+	}
+	return p, nil
+}
+
+// Rules 有効な規則
+func (p *Pipeline) Rules() []Rule {
+	return p.rules
+}
+
+// IPReputation IP アドレスの許可・拒否リスト（ip_reputation が無効なら nil）
+func (p *Pipeline) IPReputation() *ipreputation.Reputation {
+	return p.ip
+}
+
+// Close IP リストの再読み込みを止める
+func (p *Pipeline) Close() {
+	if p.stop != nil {
+		p.stop()
+	}
+}
+
+// Evaluate コメントを判定する
+//
+// tx はコメントを作成するトランザクション（データベースを参照する規則が使う）。
+// nil の場合は本文だけで判定する。
+func (p *Pipeline) Evaluate(tx *gorm.DB, in Input) (*Result, error) {
+	result := &Result{}
+	for _, rule := range p.rules {
+		hit, err := rule.Evaluate(tx, &in)
+		if err != nil {
+			return nil, fmt.Errorf("モデレーション規則 %s のエラー: %w", rule.Name(), err)
+		}
+		if hit != nil {
+			result.Hits = append(result.Hits, *hit)
+			result.Score += hit.Score
+		}
+	}
+	if result.Score > 1.0 {
+		result.Score = 1.0
+	}
+
+	switch {
+	case result.Score >= p.thresholds.Spam:
+		result.Status = StatusSpam
+	case result.Score >= p.thresholds.Pending:
+		result.Status = StatusPending // 手動確認が必要
+	default:
+		result.Status = StatusApproved // 自動承認
+	}
+	return result, nil
+}
+
+// 既定のパイプライン（Comment.BeforeCreate が使う）
+var (
+	defaultMu       sync.Mutex
+	defaultPipeline *Pipeline
+)
+
+// Default 既定のパイプライン
+//
+// 初回は DefaultFile の設定で作る。設定が読めない場合は既定の設定を使い、ログに残す。
+func Default() *Pipeline {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultPipeline != nil {
+		return defaultPipeline
+	}
+
+	config, err := LoadConfig(DefaultFile)
+	if err == nil {
+		defaultPipeline, err = New(config)
+	}
+	if err != nil {
+		log.Printf("モデレーション設定を読み込めないため既定の設定を使います: %v", err)
+		defaultPipeline, _ = New(DefaultConfig())
+	}
+	return defaultPipeline
+}
+
+// SetDefault 既定のパイプラインを差し替える（設定ファイルを指定する場合など）
+func SetDefault(p *Pipeline) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultPipeline != nil && defaultPipeline != p {
+		defaultPipeline.Close()
+	}
+	defaultPipeline = p
+}
@@ -0,0 +1,309 @@
+// internal/moderation/rules.go
+package moderation
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-db-performance-study/internal/ipreputation"
+
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+// Rule モデレーションの規則
+type Rule interface {
+	// Name 設定ファイルの項目名と同じ規則の名前
+	Name() string
+	// Evaluate 発火した場合は加点と理由を返す（発火しなければ nil）
+	//
+	// tx が nil の場合、データベースを参照する規則は判定しない。
+	Evaluate(tx *gorm.DB, in *Input) (*Hit, error)
+}
+
+// builtinKeywords キーワードの一覧ファイルを指定しない場合のスパムキーワード
+var builtinKeywords = []string{
+	"viagra", "casino", "lottery", "winner", "congratulations",
+	"click here", "make money", "work from home", "free money",
+	// 日本語のスパムキーワード
+	"出会い", "副業", "稼げる", "無料", "限定",
+}
+
+// normalize 比較用に本文を正規化（NFKC・小文字化・空白を1つにまとめる）
+//
+// 全角英数字や半角カナで書かれたキーワード・重複も同じものとして扱う。
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFKC.String(s))), " ")
+}
+
+// ContentHash 重複判定に使う本文のハッシュ（正規化した本文の SHA-256）
+func ContentHash(body string) string {
+	sum := sha256.Sum256([]byte(normalize(body)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ----------------- keywords -----------------
+
+// keywordsRule スパムキーワードを含む場合に加点
+type keywordsRule struct {
+	keywords []string // 正規化済み
+	weight   float64
+}
+
+// newKeywordsRule キーワードの一覧ファイルを読み込む（ファイルの指定がなければ組み込みの一覧）
+func newKeywordsRule(c KeywordsConfig) (*keywordsRule, error) {
+	words := builtinKeywords
+	if len(c.Files) > 0 {
+		words = nil
+		for _, path := range c.Files {
+			w, err := readKeywords(path)
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, w...)
+		}
+	}
+
+	r := &keywordsRule{weight: c.Weight}
+	seen := map[string]bool{}
+	for _, w := range words {
+		if w = normalize(w); w != "" && !seen[w] {
+			seen[w] = true
+			r.keywords = append(r.keywords, w)
+		}
+	}
+	return r, nil
+}
+
+// readKeywords 1行1語のキーワードファイルを読む（# 以降と空行は無視）
+func readKeywords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("キーワードファイルの読み込みエラー: %w", err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			words = append(words, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("キーワードファイルの読み込みエラー (%s): %w", path, err)
+	}
+	return words, nil
+}
+
+func (r *keywordsRule) Name() string { return "keywords" }
+
+func (r *keywordsRule) Evaluate(_ *gorm.DB, in *Input) (*Hit, error) {
+	body := in.normalized()
+	var matched []string
+	for _, k := range r.keywords {
+		if strings.Contains(body, k) {
+			matched = append(matched, k)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	return &Hit{Rule: r.Name(), Score: r.weight, Reason: strings.Join(matched, ", ")}, nil
+}
+
+// ----------------- urls -----------------
+
+// urlPattern 本文中の URL（スキーム付き・www. で始まるもの）
+var urlPattern = regexp.MustCompile(`(?i)\b(https?://|www\.)[^\s<>"']+`)
+
+// urlsRule URL が多すぎる場合に URL の数に比例して加点
+type urlsRule struct {
+	max          int
+	weightPerURL float64
+}
+
+func (r *urlsRule) Name() string { return "urls" }
+
+func (r *urlsRule) Evaluate(_ *gorm.DB, in *Input) (*Hit, error) {
+	n := len(urlPattern.FindAllStringIndex(in.Body, -1))
+	if n <= r.max {
+		return nil, nil
+	}
+	return &Hit{Rule: r.Name(), Score: float64(n) * r.weightPerURL, Reason: fmt.Sprintf("URL %d件（上限 %d件）", n, r.max)}, nil
+}
+
+// ----------------- length -----------------
+
+// lengthRule 極端に短い・長い本文に加点
+type lengthRule struct {
+	min, max int
+	weight   float64
+}
+
+func (r *lengthRule) Name() string { return "length" }
+
+func (r *lengthRule) Evaluate(_ *gorm.DB, in *Input) (*Hit, error) {
+	n := utf8.RuneCountInString(strings.TrimSpace(in.Body))
+	switch {
+	case n < r.min:
+		return &Hit{Rule: r.Name(), Score: r.weight, Reason: fmt.Sprintf("%d文字（下限 %d文字）", n, r.min)}, nil
+	case r.max > 0 && n > r.max:
+		return &Hit{Rule: r.Name(), Score: r.weight, Reason: fmt.Sprintf("%d文字（上限 %d文字）", n, r.max)}, nil
+	}
+	return nil, nil
+}
+
+// ----------------- rate -----------------
+
+// rateRule 同じ IP・ユーザーから短時間に多数のコメントがある場合に加点
+type rateRule struct {
+	config RateConfig
+}
+
+func (r *rateRule) Name() string { return "rate" }
+
+func (r *rateRule) Evaluate(tx *gorm.DB, in *Input) (*Hit, error) {
+	if tx == nil {
+		return nil, nil
+	}
+	since := in.at().Add(-r.config.Window)
+	checks := []struct {
+		label  string
+		column string
+		value  interface{}
+		max    int
+	}{
+		{"IP", "ip_address", in.IPAddress, r.config.MaxPerIP},
+		{"ユーザー", "user_id", in.UserID, r.config.MaxPerUser},
+	}
+
+	var reasons []string
+	for _, c := range checks {
+		if c.max <= 0 || c.value == "" || c.value == uint(0) {
+			continue
+		}
+		var count int64
+		err := comments(tx).Where(c.column+" = ? AND created_at >= ?", c.value, since).Count(&count).Error
+		if err != nil {
+			return nil, fmt.Errorf("投稿頻度の確認エラー: %w", err)
+		}
+		// これから作成する1件を含めて上限を超えるか
+		if count+1 > int64(c.max) {
+			reasons = append(reasons, fmt.Sprintf("%s: %v 以内に %d件", c.label, r.config.Window, count+1))
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+	return &Hit{Rule: r.Name(), Score: r.config.Weight, Reason: strings.Join(reasons, ", ")}, nil
+}
+
+// ----------------- duplicate -----------------
+
+// duplicateRule 同じ内容のコメントが既にある場合に加点
+type duplicateRule struct {
+	config DuplicateConfig
+}
+
+func (r *duplicateRule) Name() string { return "duplicate" }
+
+func (r *duplicateRule) Evaluate(tx *gorm.DB, in *Input) (*Hit, error) {
+	if tx == nil {
+		return nil, nil
+	}
+	q := comments(tx).Where("content_hash = ?", in.hash())
+	if r.config.SameUser {
+		q = q.Where("user_id = ?", in.UserID)
+	}
+	if r.config.Window > 0 {
+		q = q.Where("created_at >= ?", in.at().Add(-r.config.Window))
+	}
+	var ids []uint
+	if err := q.Limit(1).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("重複コメントの確認エラー: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &Hit{Rule: r.Name(), Score: r.config.Weight, Reason: fmt.Sprintf("コメント #%d と同じ内容", ids[0])}, nil
+}
+
+// ----------------- reputation -----------------
+
+// reputationRule 過去のコメントにスパムが多いユーザーに、スパム率に比例して加点
+type reputationRule struct {
+	config ReputationConfig
+}
+
+func (r *reputationRule) Name() string { return "reputation" }
+
+func (r *reputationRule) Evaluate(tx *gorm.DB, in *Input) (*Hit, error) {
+	if tx == nil || in.UserID == 0 {
+		return nil, nil
+	}
+	var stats struct {
+		Total int64
+		Spam  int64
+	}
+	recent := comments(tx).Select("status").Where("user_id = ?", in.UserID).Order("id DESC").Limit(r.config.Recent)
+	err := tx.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS recent", recent).
+		Select("COUNT(*) AS total, COALESCE(SUM(status = ?), 0) AS spam", spamStatus).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーのスパム率の確認エラー: %w", err)
+	}
+	if stats.Total < int64(r.config.MinComments) || stats.Spam == 0 {
+		return nil, nil
+	}
+	ratio := float64(stats.Spam) / float64(stats.Total)
+	return &Hit{Rule: r.Name(), Score: r.config.Weight * ratio, Reason: fmt.Sprintf("直近 %d件中 %d件がスパム", stats.Total, stats.Spam)}, nil
+}
+
+// ----------------- ip_reputation -----------------
+
+// ipRule 送信元 IP アドレスが拒否リストに一致する場合に加点
+type ipRule struct {
+	reputation *ipreputation.Reputation
+	weight     float64
+}
+
+func (r *ipRule) Name() string { return "ip_reputation" }
+
+func (r *ipRule) Evaluate(_ *gorm.DB, in *Input) (*Hit, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(in.IPAddress))
+	if err != nil {
+		return nil, nil // 未記録・解析できないアドレスは判定しない
+	}
+	e, ok := r.reputation.Lookup(addr)
+	if !ok || e.Verdict != ipreputation.Deny {
+		return nil, nil
+	}
+	return &Hit{Rule: r.Name(), Score: r.weight, Reason: fmt.Sprintf("%s は拒否リストの %s (%s)", addr, e.Prefix, e.Source)}, nil
+}
+
+// comments 判定中のトランザクションで comments テーブルを検索する
+func comments(tx *gorm.DB) *gorm.DB {
+	return tx.Session(&gorm.Session{NewDB: true}).Table("comments")
+}
+
+// at 判定の基準時刻（未指定なら現在時刻）
+func (in *Input) at() time.Time {
+	if in.CreatedAt.IsZero() {
+		return time.Now()
+	}
+	return in.CreatedAt
+}
@@ -0,0 +1,148 @@
+// internal/password/config.go
+package password
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	appconfig "go-db-performance-study/internal/config"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile パスワードハッシュ設定ファイルの既定のパス
+const DefaultFile = "configs/password.yaml"
+
+// Config 新しく作るハッシュの方式とパラメータ
+//
+// 照合はハッシュの文字列に含まれる方式で行うため、設定を変えても既存のハッシュは照合できる
+// （User.CheckPassword で一致したときに今の設定で作り直す）。
+type Config struct {
+	Algorithm Algorithm      `yaml:"algorithm"`
+	Bcrypt    BcryptConfig   `yaml:"bcrypt"`
+	Argon2id  Argon2idConfig `yaml:"argon2id"`
+}
+
+// BcryptConfig bcrypt のパラメータ
+type BcryptConfig struct {
+	Cost int `yaml:"cost"`
+}
+
+// Argon2idConfig Argon2id のパラメータ
+type Argon2idConfig struct {
+	Memory      uint32 `yaml:"memory"` // KiB
+	Iterations  uint32 `yaml:"iterations"`
+	Parallelism uint8  `yaml:"parallelism"`
+	SaltLength  uint32 `yaml:"salt_length"` // バイト
+	KeyLength   uint32 `yaml:"key_length"`  // バイト
+}
+
+// DefaultConfig 既定の設定（設定ファイルで書いた項目だけを上書きする）
+//
+// Argon2id の値は OWASP Password Storage Cheat Sheet の推奨値（19 MiB・2回・並列数 1）。
+func DefaultConfig() Config {
+	return Config{
+		Algorithm: AlgorithmBcrypt,
+		Bcrypt:    BcryptConfig{Cost: bcrypt.DefaultCost},
+		Argon2id:  Argon2idConfig{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+	}
+}
+
+// LoadConfig 設定ファイルを読み込む
+//
+// 相対パスは database.Connect の設定ファイルと同じく config.ResolvePath で解決する。
+// 既定のパスのファイルが存在しない場合は DefaultConfig を返す。
+func LoadConfig(path string) (Config, error) {
+	file := appconfig.ResolvePath(path)
+	config := DefaultConfig()
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && path == DefaultFile {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("パスワードハッシュ設定の読み込みエラー: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("%s: YAML解析エラー: %w", file, err)
+	}
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("%s: %w", file, err)
+	}
+	return config, nil
+}
+
+// Validate 設定値の検証
+func (c Config) Validate() error {
+	switch c.Algorithm {
+	case AlgorithmBcrypt:
+		if c.Bcrypt.Cost < bcrypt.MinCost || c.Bcrypt.Cost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt.cost は %d-%d の範囲で指定してください: %d", bcrypt.MinCost, bcrypt.MaxCost, c.Bcrypt.Cost)
+		}
+	case AlgorithmArgon2id:
+		a := c.Argon2id
+		if a.Iterations < 1 || a.Parallelism < 1 || a.Memory < 8*uint32(a.Parallelism) {
+			return fmt.Errorf("argon2id のパラメータが不正です（iterations・parallelism は 1 以上、memory は 8×parallelism KiB 以上）: %+v", a)
+		}
+		if a.SaltLength < 8 || a.KeyLength < 16 {
+			return fmt.Errorf("argon2id の salt_length は 8 以上、key_length は 16 以上を指定してください: %+v", a)
+		}
+	case AlgorithmFast:
+	default:
+		return fmt.Errorf("未知のパスワードハッシュの方式: %q (bcrypt/argon2id/fast)", c.Algorithm)
+	}
+	return nil
+}
+
+// New 設定の方式とパラメータの Hasher を作る
+func New(config Config) (Hasher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	switch config.Algorithm {
+	case AlgorithmBcrypt:
+		return bcryptHasher{cost: config.Bcrypt.Cost}, nil
+	case AlgorithmArgon2id:
+		return argon2idHasher{params: config.Argon2id}, nil
+	default:
+		return fastHasher{}, nil
+	}
+}
+
+// 既定の Hasher（User.BeforeCreate・User.CheckPassword が使う）
+var (
+	defaultMu     sync.Mutex
+	defaultHasher Hasher
+)
+
+// Default 既定の Hasher
+//
+// 初回は DefaultFile の設定で作る。設定が読めない場合は既定の設定を使い、ログに残す。
+func Default() Hasher {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultHasher != nil {
+		return defaultHasher
+	}
+
+	config, err := LoadConfig(DefaultFile)
+	if err == nil {
+		defaultHasher, err = New(config)
+	}
+	if err != nil {
+		log.Printf("パスワードハッシュ設定を読み込めないため既定の設定を使います: %v", err)
+		defaultHasher, _ = New(DefaultConfig())
+	}
+	return defaultHasher
+}
+
+// SetDefault 既定の Hasher を差し替える（データ生成で fast を使う場合など）
+func SetDefault(h Hasher) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultHasher = h
+}
@@ -0,0 +1,100 @@
+// internal/password/config_test.go
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	appconfig "go-db-performance-study/internal/config"
+)
+
+func TestValidate(t *testing.T) {
+	valid := DefaultConfig()
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		wantErr bool
+	}{
+		{"既定の設定", func(c *Config) {}, false},
+		{"argon2id", func(c *Config) { c.Algorithm = AlgorithmArgon2id }, false},
+		{"fast", func(c *Config) { c.Algorithm = AlgorithmFast }, false},
+		{"未知の方式", func(c *Config) { c.Algorithm = "md5" }, true},
+		{"bcrypt の cost が小さすぎる", func(c *Config) { c.Bcrypt.Cost = 3 }, true},
+		{"bcrypt の cost が大きすぎる", func(c *Config) { c.Bcrypt.Cost = 32 }, true},
+		{"argon2id の iterations が 0", func(c *Config) { c.Algorithm = AlgorithmArgon2id; c.Argon2id.Iterations = 0 }, true},
+		{"argon2id の memory が並列数に足りない", func(c *Config) {
+			c.Algorithm = AlgorithmArgon2id
+			c.Argon2id.Parallelism = 4
+			c.Argon2id.Memory = 16
+		}, true},
+		{"argon2id の salt_length が短い", func(c *Config) { c.Algorithm = AlgorithmArgon2id; c.Argon2id.SaltLength = 4 }, true},
+		{"argon2id の key_length が短い", func(c *Config) { c.Algorithm = AlgorithmArgon2id; c.Argon2id.KeyLength = 8 }, true},
+		{"使わない方式のパラメータは検証しない", func(c *Config) { c.Algorithm = AlgorithmFast; c.Bcrypt.Cost = 0 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.change(&c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, エラーの期待値 %v", err, tt.wantErr)
+			}
+			if _, err := New(c); (err != nil) != tt.wantErr {
+				t.Errorf("New() = %v, エラーの期待値 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// 書いた項目だけを上書きする
+	config, err := LoadConfig(write("argon.yaml", "algorithm: argon2id\nargon2id:\n  iterations: 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultConfig()
+	want.Algorithm = AlgorithmArgon2id
+	want.Argon2id.Iterations = 3
+	if config != want {
+		t.Errorf("LoadConfig = %+v, 期待値 %+v", config, want)
+	}
+
+	if _, err := LoadConfig(write("invalid.yaml", "algorithm: [")); err == nil {
+		t.Error("YAML の解析エラーになりません")
+	}
+	if _, err := LoadConfig(write("bad-cost.yaml", "bcrypt:\n  cost: 99\n")); err == nil {
+		t.Error("不正な値でエラーになりません")
+	}
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("指定したファイルがない場合にエラーになりません")
+	}
+}
+
+func TestLoadConfigDefaultFile(t *testing.T) {
+	// 既定のパスは APP_ROOT の下から読む
+	root := t.TempDir()
+	t.Setenv(appconfig.RootEnv, root)
+	config, err := LoadConfig(DefaultFile)
+	if err != nil || config != DefaultConfig() {
+		t.Errorf("既定のファイルがない場合は既定の設定になるはずです: %+v, %v", config, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "configs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, DefaultFile), []byte("algorithm: fast\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if config, err := LoadConfig(DefaultFile); err != nil || config.Algorithm != AlgorithmFast {
+		t.Errorf("APP_ROOT の下の設定ファイルが読まれていません: %+v, %v", config, err)
+	}
+}
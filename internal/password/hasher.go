@@ -0,0 +1,211 @@
+// internal/password/hasher.go
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithm パスワードハッシュの方式
+type Algorithm string
+
+const (
+	// AlgorithmBcrypt bcrypt（$2a$<cost>$...）
+	AlgorithmBcrypt Algorithm = "bcrypt"
+	// AlgorithmArgon2id Argon2id（PHC 形式 $argon2id$v=19$m=<KiB>,t=<回数>,p=<並列数>$<salt>$<hash>）
+	AlgorithmArgon2id Algorithm = "argon2id"
+	// AlgorithmFast ソルト付き SHA-256 を1回だけ（$fast$<salt>$<hash>）。ベンチマーク用のデータ専用で、本番では使わない
+	AlgorithmFast Algorithm = "fast"
+)
+
+// Hasher パスワードをハッシュ化する方式とパラメータ
+//
+// ハッシュの文字列に方式とパラメータを含めるため、照合は Verify で方式によらず行える。
+type Hasher interface {
+	Algorithm() Algorithm
+	Hash(password string) (string, error)
+	// NeedsRehash hash がこの Hasher と異なる方式・パラメータで作られているか
+	NeedsRehash(hash string) bool
+}
+
+// Identify ハッシュの文字列から方式を判定する（ハッシュでない場合は false）
+func Identify(hash string) (Algorithm, bool) {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		if _, err := bcrypt.Cost([]byte(hash)); err == nil {
+			return AlgorithmBcrypt, true
+		}
+	case strings.HasPrefix(hash, "$argon2id$"):
+		if _, _, _, err := parseArgon2id(hash); err == nil {
+			return AlgorithmArgon2id, true
+		}
+	case strings.HasPrefix(hash, "$fast$"):
+		if _, _, err := parseFast(hash); err == nil {
+			return AlgorithmFast, true
+		}
+	}
+	return "", false
+}
+
+// Verify hash の方式とパラメータで password を照合する
+func Verify(hash, password string) (bool, error) {
+	algorithm, ok := Identify(hash)
+	if !ok {
+		return false, fmt.Errorf("パスワードハッシュの形式が不明です")
+	}
+
+	switch algorithm {
+	case AlgorithmBcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		return err == nil, err
+	case AlgorithmArgon2id:
+		params, salt, key, _ := parseArgon2id(hash)
+		got := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(got, key) == 1, nil
+	default:
+		salt, key, _ := parseFast(hash)
+		got := fastKey(password, salt)
+		return subtle.ConstantTimeCompare(got, key) == 1, nil
+	}
+}
+
+// b64 ソルトとハッシュの符号化（PHC 形式と同じくパディングなしの標準 Base64）
+var b64 = base64.RawStdEncoding
+
+// randomSalt 暗号学的な乱数のソルト
+func randomSalt(n uint32) ([]byte, error) {
+	salt := make([]byte, n)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("ソルトの生成エラー: %w", err)
+	}
+	return salt, nil
+}
+
+// ----------------- bcrypt -----------------
+
+// bcryptHasher bcrypt
+type bcryptHasher struct {
+	cost int
+}
+
+func (h bcryptHasher) Algorithm() Algorithm { return AlgorithmBcrypt }
+
+func (h bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt のハッシュ化エラー: %w", err)
+	}
+	return string(hash), nil
+}
+
+func (h bcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+// ----------------- Argon2id -----------------
+
+// argon2idHasher Argon2id
+type argon2idHasher struct {
+	params Argon2idConfig
+}
+
+func (h argon2idHasher) Algorithm() Algorithm { return AlgorithmArgon2id }
+
+func (h argon2idHasher) Hash(password string) (string, error) {
+	p := h.params
+	salt, err := randomSalt(p.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (h argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	p := h.params
+	return params.Memory != p.Memory || params.Iterations != p.Iterations || params.Parallelism != p.Parallelism ||
+		uint32(len(salt)) != p.SaltLength || uint32(len(key)) != p.KeyLength
+}
+
+// parseArgon2id PHC 形式の Argon2id のハッシュを分解する（ソルト長・鍵長は params に入れない）
+func parseArgon2id(hash string) (params Argon2idConfig, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != string(AlgorithmArgon2id) {
+		return params, nil, nil, fmt.Errorf("Argon2id のハッシュの形式が不正です")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("Argon2id のバージョンが不正です: %s", parts[2])
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil || params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, fmt.Errorf("Argon2id のパラメータが不正です: %s", parts[3])
+	}
+	if salt, err = b64.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, fmt.Errorf("Argon2id のソルトが不正です: %w", err)
+	}
+	if key, err = b64.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("Argon2id のハッシュ値が不正です")
+	}
+	return params, salt, key, nil
+}
+
+// ----------------- fast -----------------
+
+// fastSaltLength fast のソルトのバイト数
+const fastSaltLength = 8
+
+// fastHasher ソルト付き SHA-256（総当たりに弱いのでベンチマーク用のデータ専用）
+type fastHasher struct{}
+
+func (fastHasher) Algorithm() Algorithm { return AlgorithmFast }
+
+func (fastHasher) Hash(password string) (string, error) {
+	salt, err := randomSalt(fastSaltLength)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$fast$%s$%s", b64.EncodeToString(salt), b64.EncodeToString(fastKey(password, salt))), nil
+}
+
+func (fastHasher) NeedsRehash(hash string) bool {
+	_, _, err := parseFast(hash)
+	return err != nil
+}
+
+// fastKey ソルトとパスワードを連結した SHA-256
+func fastKey(password string, salt []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, salt...), password...))
+	return sum[:]
+}
+
+// parseFast fast のハッシュを分解する
+func parseFast(hash string) (salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[1] != string(AlgorithmFast) {
+		return nil, nil, fmt.Errorf("fast のハッシュの形式が不正です")
+	}
+	if salt, err = b64.DecodeString(parts[2]); err != nil {
+		return nil, nil, fmt.Errorf("fast のソルトが不正です: %w", err)
+	}
+	if key, err = b64.DecodeString(parts[3]); err != nil || len(key) != sha256.Size {
+		return nil, nil, fmt.Errorf("fast のハッシュ値が不正です")
+	}
+	return salt, key, nil
+}
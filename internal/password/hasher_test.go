@@ -0,0 +1,123 @@
+// internal/password/hasher_test.go
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testConfigs テスト用に軽くしたパラメータの各方式の設定
+func testConfigs() []Config {
+	argon := Argon2idConfig{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}
+	return []Config{
+		{Algorithm: AlgorithmBcrypt, Bcrypt: BcryptConfig{Cost: bcrypt.MinCost}},
+		{Algorithm: AlgorithmArgon2id, Argon2id: argon},
+		{Algorithm: AlgorithmFast},
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	for _, config := range testConfigs() {
+		t.Run(string(config.Algorithm), func(t *testing.T) {
+			h, err := New(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := h.Hash("password123")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if algorithm, ok := Identify(hash); !ok || algorithm != config.Algorithm {
+				t.Errorf("Identify(%q) = %q, %v", hash, algorithm, ok)
+			}
+			if ok, err := Verify(hash, "password123"); err != nil || !ok {
+				t.Errorf("正しいパスワードが一致しません: %v %v", ok, err)
+			}
+			if ok, err := Verify(hash, "password124"); err != nil || ok {
+				t.Errorf("異なるパスワードが一致しました: %v %v", ok, err)
+			}
+			if h.NeedsRehash(hash) {
+				t.Errorf("同じ設定で作ったハッシュの作り直しが必要と判定されました: %q", hash)
+			}
+
+			// ソルトが毎回異なること
+			if again, _ := h.Hash("password123"); again == hash {
+				t.Errorf("同じパスワードから同じハッシュが作られました: %q", hash)
+			}
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	configs := testConfigs()
+	hashes := make([]string, len(configs))
+	for i, config := range configs {
+		h, _ := New(config)
+		hashes[i], _ = h.Hash("secret")
+	}
+
+	// 方式が異なれば作り直す
+	for i, config := range configs {
+		h, _ := New(config)
+		for j, hash := range hashes {
+			if got := h.NeedsRehash(hash); got != (i != j) {
+				t.Errorf("%s.NeedsRehash(%s のハッシュ) = %v", config.Algorithm, configs[j].Algorithm, got)
+			}
+		}
+	}
+
+	// パラメータが異なれば作り直す
+	stronger := configs[0]
+	stronger.Bcrypt.Cost++
+	if h, _ := New(stronger); !h.NeedsRehash(hashes[0]) {
+		t.Error("bcrypt の cost が変わっても作り直しが不要と判定されました")
+	}
+	for _, change := range []func(*Argon2idConfig){
+		func(a *Argon2idConfig) { a.Memory *= 2 },
+		func(a *Argon2idConfig) { a.Iterations++ },
+		func(a *Argon2idConfig) { a.SaltLength *= 2 },
+		func(a *Argon2idConfig) { a.KeyLength *= 2 },
+	} {
+		config := configs[1]
+		change(&config.Argon2id)
+		if h, _ := New(config); !h.NeedsRehash(hashes[1]) {
+			t.Errorf("argon2id のパラメータが %+v に変わっても作り直しが不要と判定されました", config.Argon2id)
+		}
+	}
+}
+
+func TestIdentifyRejectsNonHashes(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"password123",
+		"$2a$10$short",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA", // 要素が足りない
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5a2V5a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5a2V5a2V5",
+		"$fast$c2FsdA$" + strings.Repeat("A", 10), // ハッシュ値の長さが SHA-256 と異なる
+		"$fast$!!!$" + strings.Repeat("A", 43),
+	} {
+		if algorithm, ok := Identify(s); ok {
+			t.Errorf("Identify(%q) = %q, ハッシュではないはずです", s, algorithm)
+		}
+		if _, err := Verify(s, "password123"); err == nil {
+			t.Errorf("Verify(%q) がエラーになりません", s)
+		}
+	}
+}
+
+func TestVerifyKnownHashes(t *testing.T) {
+	// 他の実装で作られたハッシュも照合できること（$2y$ は PHP の bcrypt）
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hash := range []string{string(bcryptHash), "$2y$" + string(bcryptHash[4:])} {
+		if ok, err := Verify(hash, "password123"); err != nil || !ok {
+			t.Errorf("Verify(%q) = %v, %v", hash, ok, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// internal/repository/gorm/comment.go
+package gorm_repo
+
+import (
+	"fmt"
+	"time"
+
+	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// moderationBatchSize 一括モデレーションで1トランザクションあたりに変更するコメント数
+const moderationBatchSize = 1000
+
+// commentRepository コメントリポジトリの実装
+type commentRepository struct {
+	*BaseRepository
+}
+
+// NewCommentRepository コメントリポジトリを作成
+func NewCommentRepository(db *gorm.DB) interfaces.CommentRepository {
+	return &commentRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create コメント作成
+//
+// ステータスが未指定の場合は Comment.BeforeCreate が自動モデレーションで決め、バリデーションも行う。
+func (r *commentRepository) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+// GetByID IDでコメント取得
+func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.Preload("User").First(&comment, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("コメントが見つかりません: ID=%d", id)
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Update コメント本文の更新
+//
+// ステータスの変更は監査ログを残すため Moderate を使う。
+func (r *commentRepository) Update(id uint, updates *models.CommentForUpdate) error {
+	if err := models.ValidateStruct(updates); err != nil {
+		return fmt.Errorf("バリデーションエラー: %w", err)
+	}
+	if updates.Status != nil {
+		return fmt.Errorf("ステータスは Moderate で変更してください: ID=%d", id)
+	}
+	if updates.Body == nil {
+		return nil
+	}
+
+	return r.WithTransaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.First(&comment, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("更新対象のコメントが見つかりません: ID=%d", id)
+			}
+			return err
+		}
+
+		// BeforeUpdate が本文をエスケープし、編集済みにする
+		return tx.Model(&comment).Updates(map[string]interface{}{"body": *updates.Body}).Error
+	})
+}
+
+// Delete コメント削除（返信は外部キーで削除されない。ソフトデリートは Moderate を使う）
+func (r *commentRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Comment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("削除対象のコメントが見つかりません: ID=%d", id)
+	}
+
+	return nil
+}
+
+// ListByPost 投稿別の承認済みコメント一覧取得（古い順）
+func (r *commentRepository) ListByPost(postID uint, limit, offset int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("post_id = ? AND status = ?", postID, models.CommentStatusApproved).
+		Preload("User").
+		Order("created_at, id").
+		Limit(limit).Offset(offset).
+		Find(&comments).Error
+	return comments, err
+}
+
+// ListByUser ユーザー別コメント一覧取得
+func (r *commentRepository) ListByUser(userID uint, limit, offset int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&comments).Error
+	return comments, err
+}
+
+// ListPending 承認待ちキュー（古い順）
+func (r *commentRepository) ListPending(limit, offset int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("status = ?", models.CommentStatusPending).
+		Preload("User").
+		Order("created_at, id").
+		Limit(limit).Offset(offset).
+		Find(&comments).Error
+	return comments, err
+}
+
+// Moderate コメントのステータスを変更し、監査ログを残す
+func (r *commentRepository) Moderate(id uint, status models.CommentStatus, moderatorID uint, reason string) error {
+	if err := models.ValidateStruct(&models.CommentForUpdate{Status: &status}); err != nil {
+		return fmt.Errorf("バリデーションエラー: %w", err)
+	}
+
+	return r.WithTransaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.Select("id", "status").First(&comment, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("モデレーション対象のコメントが見つかりません: ID=%d", id)
+			}
+			return err
+		}
+		return comment.Moderate(tx, status, moderatorID, reason)
+	})
+}
+
+// ApprovePending 承認待ちのコメントをまとめて承認
+//
+// 承認待ちでなくなっていたコメントは飛ばし、承認したコメントの ID を返す。
+func (r *commentRepository) ApprovePending(ids []uint, moderatorID uint, reason string) ([]uint, error) {
+	return r.moderatePending(ids, models.CommentStatusApproved, moderatorID, reason)
+}
+
+// RejectPending 承認待ちのコメントをまとめてスパムにする
+func (r *commentRepository) RejectPending(ids []uint, moderatorID uint, reason string) ([]uint, error) {
+	return r.moderatePending(ids, models.CommentStatusSpam, moderatorID, reason)
+}
+
+// moderatePending 承認待ちのコメントを moderationBatchSize 件ずつのトランザクションで変更
+//
+// 1トランザクションで大量の行をロックしないよう分ける（途中で失敗しても、それまでの変更は残る）。
+func (r *commentRepository) moderatePending(ids []uint, status models.CommentStatus, moderatorID uint, reason string) ([]uint, error) {
+	var changed []uint
+	for start := 0; start < len(ids); start += moderationBatchSize {
+		end := start + moderationBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		c, err := models.ModerateComments(r.db, ids[start:end], models.CommentStatusPending, status, moderatorID, reason)
+		if err != nil {
+			return changed, err
+		}
+		changed = append(changed, c...)
+	}
+	return changed, nil
+}
+
+// ListModerationActions コメントの監査ログ（古い順）
+func (r *commentRepository) ListModerationActions(commentID uint) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	err := r.db.Where("comment_id = ?", commentID).
+		Preload("Moderator").
+		Order("created_at, id").
+		Find(&actions).Error
+	return actions, err
+}
+
+// ListActionsByModerator モデレーター別の監査ログ（新しい順）
+func (r *commentRepository) ListActionsByModerator(moderatorID uint, limit, offset int) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	err := r.db.Where("moderator_id = ?", moderatorID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&actions).Error
+	return actions, err
+}
+
+// GetModeratorActivity 期間内 [from, to) のモデレーター別の操作件数（件数の多い順）
+//
+// 自動処理（moderator_id が NULL）の操作は含めない。
+func (r *commentRepository) GetModeratorActivity(from, to time.Time, limit int) ([]models.ModeratorActivity, error) {
+	var activity []models.ModeratorActivity
+	err := r.db.Table("moderation_actions ma").
+		Select(`ma.moderator_id, u.name AS moderator_name,
+			COUNT(*) AS action_count,
+			SUM(ma.to_status = ?) AS approved_count,
+			SUM(ma.to_status = ?) AS spam_count,
+			SUM(ma.to_status = ?) AS deleted_count,
+			MAX(ma.created_at) AS last_action_at`,
+			models.CommentStatusApproved, models.CommentStatusSpam, models.CommentStatusDeleted).
+		Joins("JOIN users u ON u.id = ma.moderator_id").
+		Where("ma.created_at >= ? AND ma.created_at < ?", from, to).
+		Group("ma.moderator_id, u.name").
+		Order("action_count DESC").
+		Limit(limit).
+		Scan(&activity).Error
+	if err != nil {
+		return nil, fmt.Errorf("モデレーター別の操作件数の取得エラー: %w", err)
+	}
+	return activity, nil
+}
+
+// Count コメント総数取得
+func (r *commentRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Comment{}).Count(&count).Error
+	return count, err
+}
+
+// CountByStatus ステータス別コメント数取得
+func (r *commentRepository) CountByStatus(status models.CommentStatus) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Comment{}).Where("status = ?", status).Count(&count).Error
+	return count, err
+}
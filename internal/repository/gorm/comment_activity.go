@@ -0,0 +1,174 @@
+// internal/repository/gorm/comment_activity.go
+package gorm_repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-db-performance-study/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// activityColumns 集計結果に付けるユーザー名（集計の別名 a と users u を結合する）
+const activityColumns = "a.user_id, u.name AS user_name, a.comment_count, a.last_comment, a.avg_length, a.spam_ratio"
+
+// GetTopCommenters 期間 [from, to) のコメント数の多いユーザー（コメント数の多い順）
+//
+// 上位 limit 人を求めてからユーザー名を結合する（削除されたユーザーは除かれるため limit 人より少ないことがある）。
+func (r *commentRepository) GetTopCommenters(from, to time.Time, limit int, source models.ActivitySource) ([]models.UserCommentActivity, error) {
+	q, err := r.activityQuery(from, to, source)
+	if err != nil {
+		return nil, err
+	}
+	top := q.Order("comment_count DESC, user_id").Limit(limit)
+
+	var activity []models.UserCommentActivity
+	err = r.db.Table("(?) AS a", top).
+		Select(activityColumns).
+		Joins("JOIN users u ON u.id = a.user_id").
+		Order("a.comment_count DESC, a.user_id").
+		Scan(&activity).Error
+	if err != nil {
+		return nil, fmt.Errorf("コメント数の多いユーザーの取得エラー (%s): %w", source, err)
+	}
+	return activity, nil
+}
+
+// GetUserCommentActivity 期間 [from, to) のユーザーのコメント活動
+//
+// 期間内にコメントがない場合は UserID だけを設定した値を返す。
+func (r *commentRepository) GetUserCommentActivity(userID uint, from, to time.Time, source models.ActivitySource) (*models.UserCommentActivity, error) {
+	q, err := r.activityQuery(from, to, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var activity []models.UserCommentActivity
+	err = r.db.Table("(?) AS a", q.Where("user_id = ?", userID)).
+		Select(activityColumns).
+		Joins("JOIN users u ON u.id = a.user_id").
+		Scan(&activity).Error
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーのコメント活動の取得エラー (%s): %w", source, err)
+	}
+	if len(activity) == 0 {
+		return &models.UserCommentActivity{UserID: userID}, nil
+	}
+	return &activity[0], nil
+}
+
+// activityQuery ユーザー別の集計（user_id, comment_count, last_comment, avg_length, spam_ratio）
+//
+// rollup は日単位のため、期間に含まれる丸1日分だけを事前集計から読み、
+// 日の途中から始まる・終わる端の部分は comments から集計して合算する（live と同じ期間になる）。
+func (r *commentRepository) activityQuery(from, to time.Time, source models.ActivitySource) (*gorm.DB, error) {
+	switch source {
+	case models.ActivityLive:
+		return r.db.Table("comments").
+			Select(`user_id, COUNT(*) AS comment_count, MAX(created_at) AS last_comment,
+				AVG(CHAR_LENGTH(body)) AS avg_length, AVG(status = ?) AS spam_ratio`, models.CommentStatusSpam).
+			Where("created_at >= ? AND created_at < ?", from, to).
+			Group("user_id"), nil
+	case models.ActivityRollup:
+		firstDay, endDay := models.StartOfDay(from), models.StartOfDay(to)
+		if firstDay.Before(from) {
+			firstDay = firstDay.AddDate(0, 0, 1)
+		}
+		if !firstDay.Before(endDay) {
+			return r.activityQuery(from, to, models.ActivityLive) // 丸1日分を含まない
+		}
+
+		if from.Equal(firstDay) && to.Equal(endDay) {
+			return r.db.Table("comment_daily_rollups").
+				Select(rollupActivityColumns).
+				Where("day >= ? AND day < ?", firstDay, endDay).
+				Group("user_id"), nil
+		}
+
+		days := r.db.Table("comment_daily_rollups").
+			Select("user_id, comment_count, spam_count, total_length, last_comment_at").
+			Where("day >= ? AND day < ?", firstDay, endDay)
+		// 端の部分（from から最初の日の始まりまで、最後の日の始まりから to まで）
+		var (
+			edgeRanges []string
+			edgeArgs   []interface{}
+		)
+		if from.Before(firstDay) {
+			edgeRanges = append(edgeRanges, "(created_at >= ? AND created_at < ?)")
+			edgeArgs = append(edgeArgs, from, firstDay)
+		}
+		if endDay.Before(to) {
+			edgeRanges = append(edgeRanges, "(created_at >= ? AND created_at < ?)")
+			edgeArgs = append(edgeArgs, endDay, to)
+		}
+		edges := r.db.Table("comments").
+			Select(`user_id, COUNT(*) AS comment_count, SUM(status = ?) AS spam_count,
+				SUM(CHAR_LENGTH(body)) AS total_length, MAX(created_at) AS last_comment_at`, models.CommentStatusSpam).
+			Where(strings.Join(edgeRanges, " OR "), edgeArgs...).
+			Group("user_id")
+		return r.db.Table("(? UNION ALL ?) AS d", days, edges).
+			Select(rollupActivityColumns).
+			Group("user_id"), nil
+	}
+	return nil, fmt.Errorf("未知の集計方法: %s (live/rollup)", source)
+}
+
+// rollupActivityColumns 日別集計（と端の部分の集計）を合算する列
+const rollupActivityColumns = `user_id, SUM(comment_count) AS comment_count, MAX(last_comment_at) AS last_comment,
+	SUM(total_length) / SUM(comment_count) AS avg_length, SUM(spam_count) / SUM(comment_count) AS spam_ratio`
+
+// RefreshActivityRollup 日別の事前集計を作り直す
+//
+// from の日から to の直前を含む日まで、1日ずつのトランザクションで削除と INSERT ... SELECT を行う。
+// from・to がゼロ値の場合はコメントの最初・最後の日まで。作り直した日数と行数を返す。
+func (r *commentRepository) RefreshActivityRollup(from, to time.Time) (int, int64, error) {
+	if from.IsZero() || to.IsZero() {
+		var span struct {
+			First *time.Time
+			Last  *time.Time
+		}
+		err := r.db.Model(&models.Comment{}).
+			Select("MIN(created_at) AS first, MAX(created_at) AS last").
+			Scan(&span).Error
+		if err != nil {
+			return 0, 0, fmt.Errorf("コメントの期間の取得エラー: %w", err)
+		}
+		if span.First == nil {
+			return 0, 0, nil // コメントがない
+		}
+		if from.IsZero() {
+			from = *span.First
+		}
+		if to.IsZero() {
+			to = span.Last.Add(time.Nanosecond)
+		}
+	}
+
+	var (
+		days int
+		rows int64
+	)
+	for day := models.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		err := r.WithTransaction(func(tx *gorm.DB) error {
+			if err := tx.Where("day = ?", day).Delete(&models.CommentDailyRollup{}).Error; err != nil {
+				return err
+			}
+			result := tx.Exec(`INSERT INTO comment_daily_rollups
+				(user_id, day, comment_count, spam_count, total_length, last_comment_at, refreshed_at)
+				SELECT user_id, ?, COUNT(*), SUM(status = ?), SUM(CHAR_LENGTH(body)), MAX(created_at), ?
+				FROM comments WHERE created_at >= ? AND created_at < ?
+				GROUP BY user_id`,
+				day, models.CommentStatusSpam, time.Now(), day, next)
+			rows += result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			return days, rows, fmt.Errorf("日別集計の更新エラー (%s): %w", day.Format("2006-01-02"), err)
+		}
+		days++
+	}
+	return days, rows, nil
+}
@@ -0,0 +1,224 @@
+// internal/repository/gorm/comment_thread.go
+package gorm_repo
+
+import (
+	"fmt"
+	"sort"
+
+	"go-db-performance-study/internal/models"
+)
+
+// threadCTE ルートコメントから返信を WITH RECURSIVE でたどる（sort_key は path と同じ形式の並び順）
+var threadCTE = fmt.Sprintf(`WITH RECURSIVE thread (id, depth, sort_key) AS (
+	SELECT id, 0, CAST(CONCAT(LPAD(id, %[1]d, '0'), '/') AS CHAR(%[2]d)) FROM comments WHERE id = ?
+	UNION ALL
+	SELECT c.id, t.depth + 1, CONCAT(t.sort_key, LPAD(c.id, %[1]d, '0'), '/')
+	FROM comments c JOIN thread t ON c.parent_id = t.id
+	WHERE t.depth < ?
+)`, models.CommentPathDigits, (models.MaxThreadDepth+1)*(models.CommentPathDigits+1))
+
+// ancestorsCTE コメントから親を WITH RECURSIVE でたどる
+const ancestorsCTE = `WITH RECURSIVE ancestors (id, parent_id, depth) AS (
+	SELECT id, parent_id, 0 FROM comments WHERE id = ?
+	UNION ALL
+	SELECT c.id, c.parent_id, a.depth + 1
+	FROM comments c JOIN ancestors a ON c.id = a.parent_id
+	WHERE a.depth < ?
+)`
+
+// GetThread ルートコメントと全ての返信（深さ優先の順、兄弟は ID 順）
+//
+// 読み込み方法によらず同じ結果を返す。Depth はルートからの段数（ルートは 0）。
+func (r *commentRepository) GetThread(rootID uint, strategy models.ThreadStrategy) ([]models.ThreadComment, error) {
+	var (
+		thread []models.ThreadComment
+		err    error
+	)
+	switch strategy {
+	case models.ThreadAdjacency:
+		thread, err = r.threadByAdjacency(rootID)
+	case models.ThreadRecursive:
+		err = r.db.Raw(threadCTE+`
+			SELECT c.*, t.depth FROM comments c JOIN thread t ON t.id = c.id ORDER BY t.sort_key`,
+			rootID, models.MaxThreadDepth).Scan(&thread).Error
+	case models.ThreadPath:
+		thread, err = r.threadByPath(rootID)
+	default:
+		return nil, fmt.Errorf("未知のスレッドの読み込み方法: %s (adjacency/recursive/path)", strategy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("スレッドの取得エラー (%s): %w", strategy, err)
+	}
+	if len(thread) == 0 {
+		return nil, fmt.Errorf("コメントが見つかりません: ID=%d", rootID)
+	}
+	return thread, nil
+}
+
+// threadByAdjacency 1段ずつ子のコメントを読み込み、深さ優先の順に並べ直す
+func (r *commentRepository) threadByAdjacency(rootID uint) ([]models.ThreadComment, error) {
+	var root []models.Comment
+	if err := r.db.Where("id = ?", rootID).Find(&root).Error; err != nil {
+		return nil, err
+	}
+	if len(root) == 0 {
+		return nil, nil
+	}
+
+	children := map[uint][]models.Comment{}
+	frontier := []uint{rootID}
+	for depth := 0; depth < models.MaxThreadDepth && len(frontier) > 0; depth++ {
+		var replies []models.Comment
+		if err := r.db.Where("parent_id IN ?", frontier).Order("id").Find(&replies).Error; err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, c := range replies {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+			frontier = append(frontier, c.ID)
+		}
+	}
+
+	// 他の読み込み方法と同じく MaxThreadDepth 段までたどり、parent_id が循環していても同じ行は1回だけ並べる
+	var thread []models.ThreadComment
+	visited := map[uint]bool{}
+	var walk func(c models.Comment, depth int)
+	walk = func(c models.Comment, depth int) {
+		if visited[c.ID] {
+			return
+		}
+		visited[c.ID] = true
+		thread = append(thread, models.ThreadComment{Comment: c, Depth: depth})
+		if depth >= models.MaxThreadDepth {
+			return
+		}
+		replies := children[c.ID]
+		sort.Slice(replies, func(i, j int) bool { return replies[i].ID < replies[j].ID })
+		for _, reply := range replies {
+			walk(reply, depth+1)
+		}
+	}
+	walk(root[0], 0)
+	return thread, nil
+}
+
+// threadByPath ルートの path で前方一致する行を path の順に読み込む
+func (r *commentRepository) threadByPath(rootID uint) ([]models.ThreadComment, error) {
+	var paths []string
+	if err := r.db.Model(&models.Comment{}).Where("id = ?", rootID).Pluck("path", &paths).Error; err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if paths[0] == "" {
+		return nil, fmt.Errorf("コメントの path が未作成です: ID=%d（maintenance backfill-comment-paths を実行してください）", rootID)
+	}
+
+	var thread []models.ThreadComment
+	err := r.db.Table("comments").
+		Select("*, (CHAR_LENGTH(path) - ?) DIV ? AS depth", len(paths[0]), models.CommentPathDigits+1).
+		Where("path LIKE ?", paths[0]+"%").
+		Order("path").
+		Scan(&thread).Error
+	return thread, err
+}
+
+// GetDepth コメントの段数（ルートコメントは 0）
+func (r *commentRepository) GetDepth(id uint, strategy models.ThreadStrategy) (int, error) {
+	var (
+		depth *int
+		err   error
+	)
+	switch strategy {
+	case models.ThreadAdjacency:
+		depth, err = r.depthByAdjacency(id)
+	case models.ThreadRecursive:
+		// MaxThreadDepth 段目の祖先にまだ親があれば、上限より深い
+		var row struct {
+			Depth     *int
+			Truncated bool
+		}
+		err = r.db.Raw(ancestorsCTE+`
+			SELECT MAX(depth) AS depth, COALESCE(MAX(depth = ? AND parent_id IS NOT NULL), 0) AS truncated FROM ancestors`,
+			id, models.MaxThreadDepth, models.MaxThreadDepth).Scan(&row).Error
+		if err == nil && row.Truncated {
+			err = fmt.Errorf("ID=%d: %w", id, models.ErrThreadTooDeep)
+		}
+		depth = row.Depth
+	case models.ThreadPath:
+		var paths []string
+		err = r.db.Model(&models.Comment{}).Where("id = ?", id).Pluck("path", &paths).Error
+		if err == nil && len(paths) > 0 {
+			if paths[0] == "" {
+				return 0, fmt.Errorf("コメントの path がありません: ID=%d（未作成の場合は maintenance backfill-comment-paths を実行してください。%d段より深い返信は path を持ちません）",
+					id, models.MaxThreadDepth)
+			}
+			d := models.CommentPathDepth(paths[0])
+			depth = &d
+		}
+	default:
+		return 0, fmt.Errorf("未知のスレッドの読み込み方法: %s (adjacency/recursive/path)", strategy)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("コメントの段数の取得エラー (%s): %w", strategy, err)
+	}
+	if depth == nil {
+		return 0, fmt.Errorf("コメントが見つかりません: ID=%d", id)
+	}
+	return *depth, nil
+}
+
+// depthByAdjacency 親の ID を1段ずつ読み込む
+func (r *commentRepository) depthByAdjacency(id uint) (*int, error) {
+	var parents []*uint
+	if err := r.db.Model(&models.Comment{}).Where("id = ?", id).Pluck("parent_id", &parents).Error; err != nil {
+		return nil, err
+	}
+	if len(parents) == 0 {
+		return nil, nil
+	}
+
+	// 見つかった親の数を数える（WITH RECURSIVE の ancestors と同じ）
+	depth, parent := 0, parents[0]
+	for parent != nil && depth < models.MaxThreadDepth {
+		parents = parents[:0]
+		if err := r.db.Model(&models.Comment{}).Where("id = ?", *parent).Pluck("parent_id", &parents).Error; err != nil {
+			return nil, err
+		}
+		if len(parents) == 0 {
+			break // 親が削除されている（ここまでの段数を返す）
+		}
+		depth++
+		parent = parents[0]
+	}
+	if parent != nil && depth >= models.MaxThreadDepth {
+		return nil, fmt.Errorf("ID=%d: %w", id, models.ErrThreadTooDeep) // 上限の段数でまだ親がある
+	}
+	return &depth, nil
+}
+
+// CountReplies コメントごとの承認済みの返信数（1クエリでまとめて数える。返信がなければ含まない）
+func (r *commentRepository) CountReplies(ids []uint) (map[uint]int64, error) {
+	counts := map[uint]int64{}
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParentID uint
+		Count    int64
+	}
+	err := r.db.Model(&models.Comment{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ? AND status = ?", ids, models.CommentStatusApproved).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("返信数の取得エラー: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.ParentID] = row.Count
+	}
+	return counts, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"go-db-performance-study/internal/models"
 	"go-db-performance-study/internal/repository/interfaces"
+	slugpkg "go-db-performance-study/internal/slug"
 
 	"gorm.io/gorm"
 )
@@ -47,25 +48,53 @@ func (r *postRepository) GetByID(id uint) (*models.Post, error) {
 }
 
 // GetBySlug スラッグで投稿取得
-func (r *postRepository) GetBySlug(slug string) (*models.Post, error) {
+//
+// 現在の slug に一致する投稿がなければ slug 履歴を探し、見つかれば現在の投稿と moved=true を返す
+// （呼び出し側は現在の slug へリダイレクトする）。
+func (r *postRepository) GetBySlug(slug string) (*models.Post, bool, error) {
 	var post models.Post
 	err := r.db.Where("slug = ?", slug).Preload("User").Preload("Tags").First(&post).Error
+	if err == nil {
+		return &post, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+
+	id, err := r.findMovedSlug(slugpkg.Posts, slug)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("投稿が見つかりません: Slug=%s", slug)
+		return nil, false, err
+	}
+	if id != 0 {
+		err = r.db.Preload("User").Preload("Tags").First(&post, id).Error
+		if err == nil {
+			return &post, true, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, false, err
 		}
-		return nil, err
 	}
-	return &post, nil
+	return nil, false, fmt.Errorf("投稿が見つかりません: Slug=%s", slug)
 }
 
 // Update 投稿更新
+//
+// slug を変更した場合は Post.BeforeUpdate が変更前の slug を履歴に残す。
 func (r *postRepository) Update(id uint, updates *models.PostForUpdate) error {
 	if err := models.ValidateStruct(updates); err != nil {
 		return fmt.Errorf("バリデーションエラー: %w", err)
 	}
 
 	return r.WithTransaction(func(tx *gorm.DB) error {
+		// フックが変更前の値を参照できるよう、先に読み込んでから更新する
+		var post models.Post
+		if err := tx.First(&post, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("更新対象の投稿が見つかりません: ID=%d", id)
+			}
+			return err
+		}
+
 		// 投稿本体を更新するための map に限定
 		updateData := map[string]interface{}{}
 		if updates.Title != nil {
@@ -74,27 +103,21 @@ func (r *postRepository) Update(id uint, updates *models.PostForUpdate) error {
 		if updates.Body != nil {
 			updateData["body"] = *updates.Body
 		}
+		if updates.Slug != nil {
+			updateData["slug"] = *updates.Slug
+		}
 		if updates.Status != nil {
 			updateData["status"] = *updates.Status
 		}
 
 		if len(updateData) > 0 {
-			result := tx.Model(&models.Post{}).Where("id = ?", id).Updates(updateData)
-			if result.Error != nil {
-				return result.Error
-			}
-			if result.RowsAffected == 0 {
-				return fmt.Errorf("更新対象の投稿が見つかりません: ID=%d", id)
+			if err := tx.Model(&post).Updates(updateData).Error; err != nil {
+				return err
 			}
 		}
 
 		// タグ関連付けを更新
 		if updates.TagIDs != nil {
-			var post models.Post
-			if err := tx.First(&post, id).Error; err != nil {
-				return err
-			}
-
 			var tags []models.Tag
 			if len(updates.TagIDs) > 0 {
 				if err := tx.Find(&tags, updates.TagIDs).Error; err != nil {
@@ -0,0 +1,24 @@
+// internal/repository/gorm/slug_history.go
+package gorm_repo
+
+import (
+	"fmt"
+
+	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/slug"
+)
+
+// findMovedSlug slug 履歴から旧 slug の現在の行の ID を探す（見つからなければ 0）
+func (r *BaseRepository) findMovedSlug(scope slug.Scope, s string) (uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.SlugHistory{}).
+		Where("entity_type = ? AND slug = ?", scope.Table, s).
+		Limit(1).Pluck("entity_id", &ids).Error
+	if err != nil {
+		return 0, fmt.Errorf("slug 履歴の検索エラー: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
@@ -0,0 +1,156 @@
+// internal/repository/gorm/tag.go
+package gorm_repo
+
+import (
+	"fmt"
+
+	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/repository/interfaces"
+	slugpkg "go-db-performance-study/internal/slug"
+
+	"gorm.io/gorm"
+)
+
+// tagRepository タグリポジトリの実装
+type tagRepository struct {
+	*BaseRepository
+}
+
+// NewTagRepository タグリポジトリを作成
+func NewTagRepository(db *gorm.DB) interfaces.TagRepository {
+	return &tagRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create タグ作成
+func (r *tagRepository) Create(tag *models.Tag) error {
+	if err := tag.Validate(); err != nil {
+		return fmt.Errorf("バリデーションエラー: %w", err)
+	}
+
+	return r.db.Create(tag).Error
+}
+
+// GetByID IDでタグ取得
+func (r *tagRepository) GetByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.First(&tag, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("タグが見つかりません: ID=%d", id)
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetBySlug スラッグでタグ取得
+//
+// 現在の slug に一致するタグがなければ slug 履歴を探し、見つかれば現在のタグと moved=true を返す。
+func (r *tagRepository) GetBySlug(slug string) (*models.Tag, bool, error) {
+	var tag models.Tag
+	err := r.db.Where("slug = ?", slug).First(&tag).Error
+	if err == nil {
+		return &tag, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+
+	id, err := r.findMovedSlug(slugpkg.Tags, slug)
+	if err != nil {
+		return nil, false, err
+	}
+	if id != 0 {
+		err = r.db.First(&tag, id).Error
+		if err == nil {
+			return &tag, true, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, false, err
+		}
+	}
+	return nil, false, fmt.Errorf("タグが見つかりません: Slug=%s", slug)
+}
+
+// Update タグ更新
+//
+// 名前を変更すると Tag.BeforeUpdate が slug を作り直し、変更前の slug を履歴に残す。
+func (r *tagRepository) Update(id uint, updates *models.TagForUpdate) error {
+	if err := models.ValidateStruct(updates); err != nil {
+		return fmt.Errorf("バリデーションエラー: %w", err)
+	}
+
+	return r.WithTransaction(func(tx *gorm.DB) error {
+		// フックが変更前の値を参照できるよう、先に読み込んでから更新する
+		var tag models.Tag
+		if err := tx.First(&tag, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("更新対象のタグが見つかりません: ID=%d", id)
+			}
+			return err
+		}
+
+		updateData := map[string]interface{}{}
+		if updates.Name != nil {
+			updateData["name"] = *updates.Name
+		}
+		if updates.Color != nil {
+			updateData["color"] = *updates.Color
+		}
+		if updates.Description != nil {
+			updateData["description"] = *updates.Description
+		}
+		if updates.IsActive != nil {
+			updateData["is_active"] = *updates.IsActive
+		}
+		if len(updateData) == 0 {
+			return nil
+		}
+
+		return tx.Model(&tag).Updates(updateData).Error
+	})
+}
+
+// Delete タグ削除
+//
+// slug 履歴は残る（GetBySlug は見つからない扱いにし、maintenance prune-slugs で削除する）。
+func (r *tagRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.Tag{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("削除対象のタグが見つかりません: ID=%d", id)
+	}
+
+	return nil
+}
+
+// List タグ一覧取得（名前順）
+func (r *tagRepository) List(limit, offset int) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Order("name").
+		Limit(limit).Offset(offset).
+		Find(&tags).Error
+	return tags, err
+}
+
+// GetPopularTags 人気タグ取得（投稿数順）
+func (r *tagRepository) GetPopularTags(limit int) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Where("is_active = ?", true).
+		Order("post_count DESC").
+		Limit(limit).
+		Find(&tags).Error
+	return tags, err
+}
+
+// Count タグ総数取得
+func (r *tagRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Tag{}).Count(&count).Error
+	return count, err
+}
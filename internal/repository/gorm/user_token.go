@@ -0,0 +1,103 @@
+// internal/repository/gorm/user_token.go
+package gorm_repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-db-performance-study/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetByVerificationToken メール認証トークンのハッシュでユーザー取得（idx_user_verification_token）
+func (r *userRepository) GetByVerificationToken(tokenHash string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("email_verification_token = ?", tokenHash).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("メール認証トークンでのユーザー取得エラー: %w", err)
+	}
+	return &user, nil
+}
+
+// SetVerificationToken メール認証トークンを設定（発行済みのトークンは無効になる）
+func (r *userRepository) SetVerificationToken(id uint, tokenHash string, expiresAt time.Time) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email_verification_token":      tokenHash,
+		"email_verification_expires_at": expiresAt,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("メール認証トークンの設定エラー: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("更新対象のユーザーが見つかりません: ID=%d", id)
+	}
+	return nil
+}
+
+// MarkEmailVerified メール認証済みにしてトークンを消す
+//
+// トークンが tokenHash のままの場合だけ更新する（同じトークンを同時に使っても1回だけ成功する）。
+// 更新した場合は true を返す。
+func (r *userRepository) MarkEmailVerified(id uint, tokenHash string, verifiedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND email_verification_token = ?", id, tokenHash).
+		Updates(map[string]interface{}{
+			"email_verified_at":             verifiedAt,
+			"email_verification_token":      nil,
+			"email_verification_expires_at": nil,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("メール認証の更新エラー: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByRememberToken ログイン保持トークンのハッシュでユーザー取得（idx_user_remember_token）
+func (r *userRepository) GetByRememberToken(tokenHash string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("remember_token = ?", tokenHash).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ログイン保持トークンでのユーザー取得エラー: %w", err)
+	}
+	return &user, nil
+}
+
+// SetRememberToken ログイン保持トークンを設定（nil の場合は消す）
+func (r *userRepository) SetRememberToken(id uint, tokenHash *string, expiresAt *time.Time) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"remember_token":            tokenHash,
+		"remember_token_expires_at": expiresAt,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("ログイン保持トークンの設定エラー: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("更新対象のユーザーが見つかりません: ID=%d", id)
+	}
+	return nil
+}
+
+// RotateRememberToken ログイン保持トークンを新しいトークンに置き換える
+//
+// トークンが oldHash のままの場合だけ更新する（同じトークンを同時に使っても1回だけ成功する）。
+// 置き換えた場合は true を返す。
+func (r *userRepository) RotateRememberToken(id uint, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND remember_token = ?", id, oldHash).
+		Updates(map[string]interface{}{
+			"remember_token":            newHash,
+			"remember_token_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("ログイン保持トークンの更新エラー: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
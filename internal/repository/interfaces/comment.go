@@ -0,0 +1,46 @@
+// internal/repository/interfaces/comment.go
+package interfaces
+
+import (
+	"time"
+
+	"go-db-performance-study/internal/models"
+)
+
+// CommentRepository コメントリポジトリインターフェース
+type CommentRepository interface {
+	// 基本CRUD
+	Create(comment *models.Comment) error
+	GetByID(id uint) (*models.Comment, error)
+	Update(id uint, updates *models.CommentForUpdate) error // ステータスは Moderate で変更する
+	Delete(id uint) error
+
+	// 一覧取得
+	ListByPost(postID uint, limit, offset int) ([]models.Comment, error)
+	ListByUser(userID uint, limit, offset int) ([]models.Comment, error)
+	ListPending(limit, offset int) ([]models.Comment, error) // 承認待ちキュー（古い順）
+
+	// モデレーション（ステータスを変更した行ごとに監査ログを残す）
+	Moderate(id uint, status models.CommentStatus, moderatorID uint, reason string) error
+	ApprovePending(ids []uint, moderatorID uint, reason string) (approved []uint, err error)
+	RejectPending(ids []uint, moderatorID uint, reason string) (rejected []uint, err error)
+
+	// 監査ログ
+	ListModerationActions(commentID uint) ([]models.ModerationAction, error)
+	ListActionsByModerator(moderatorID uint, limit, offset int) ([]models.ModerationAction, error)
+	GetModeratorActivity(from, to time.Time, limit int) ([]models.ModeratorActivity, error)
+
+	// 統計
+	Count() (int64, error)
+	CountByStatus(status models.CommentStatus) (int64, error)
+
+	// 活動統計（期間 [from, to)。source でその場で集計するか日別の事前集計を使うかを選ぶ）
+	GetTopCommenters(from, to time.Time, limit int, source models.ActivitySource) ([]models.UserCommentActivity, error)
+	GetUserCommentActivity(userID uint, from, to time.Time, source models.ActivitySource) (*models.UserCommentActivity, error)
+	RefreshActivityRollup(from, to time.Time) (days int, rows int64, err error)
+
+	// スレッド（strategy で読み込み方法を選ぶ。どの方法でも同じ結果を返す）
+	GetThread(rootID uint, strategy models.ThreadStrategy) ([]models.ThreadComment, error)
+	GetDepth(id uint, strategy models.ThreadStrategy) (int, error)
+	CountReplies(ids []uint) (map[uint]int64, error)
+}
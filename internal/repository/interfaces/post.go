@@ -11,7 +11,7 @@ type PostRepository interface {
     // 基本CRUD
     Create(post *models.Post) error
     GetByID(id uint) (*models.Post, error)
-    GetBySlug(slug string) (*models.Post, error)
+    GetBySlug(slug string) (post *models.Post, moved bool, err error) // moved: 旧スラッグから辿った
     Update(id uint, updates *models.PostForUpdate) error
     Delete(id uint) error
     
@@ -0,0 +1,23 @@
+// internal/repository/interfaces/tag.go
+package interfaces
+
+import (
+	"go-db-performance-study/internal/models"
+)
+
+// TagRepository タグリポジトリインターフェース
+type TagRepository interface {
+	// 基本CRUD
+	Create(tag *models.Tag) error
+	GetByID(id uint) (*models.Tag, error)
+	GetBySlug(slug string) (tag *models.Tag, moved bool, err error) // moved: 旧スラッグから辿った
+	Update(id uint, updates *models.TagForUpdate) error
+	Delete(id uint) error
+
+	// 一覧取得
+	List(limit, offset int) ([]models.Tag, error)
+	GetPopularTags(limit int) ([]models.Tag, error)
+
+	// 統計
+	Count() (int64, error)
+}
@@ -2,6 +2,8 @@
 package interfaces
 
 import (
+    "time"
+
     "go-db-performance-study/internal/models"
 )
 
@@ -25,4 +27,12 @@ type UserRepository interface {
     // 統計
     Count() (int64, error)
     CountByStatus(verified bool) (int64, error)
+
+    // トークン（引数・戻り値はトークンの SHA-256。平文は account.Service だけが扱う）
+    GetByVerificationToken(tokenHash string) (*models.User, error)
+    SetVerificationToken(id uint, tokenHash string, expiresAt time.Time) error
+    MarkEmailVerified(id uint, tokenHash string, verifiedAt time.Time) (bool, error)
+    GetByRememberToken(tokenHash string) (*models.User, error)
+    SetRememberToken(id uint, tokenHash *string, expiresAt *time.Time) error
+    RotateRememberToken(id uint, oldHash, newHash string, expiresAt time.Time) (bool, error)
 }
\ No newline at end of file
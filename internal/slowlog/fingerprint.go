@@ -0,0 +1,90 @@
+// internal/slowlog/fingerprint.go
+package slowlog
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	blockCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	lineCommentRe  = regexp.MustCompile(`(?m)(--|#)[^\n]*$`)
+	numberRe       = regexp.MustCompile(`\b(0x[0-9a-f]+|[0-9]+(\.[0-9]+)?(e[+-]?[0-9]+)?)\b`)
+	signedNumberRe = regexp.MustCompile(`([=<>(,]\s*)-\?`)
+	spaceRe        = regexp.MustCompile(`\s+`)
+	inListRe       = regexp.MustCompile(`\bin\s*\(\s*\?(\s*,\s*\?)*\s*\)`)
+	valuesListRe   = regexp.MustCompile(`\b(values?)\s*\((?:[^()]|\([^()]*\))*\)(\s*,\s*\((?:[^()]|\([^()]*\))*\))*`)
+	limitRe        = regexp.MustCompile(`\blimit \?(\s*,\s*\?)?( offset \?)?`)
+)
+
+// Fingerprint リテラルを正規化したクエリの指紋を返す
+//
+// 文字列・数値リテラルを ? に置き換え、IN リストと VALUES の行数、
+// 空白・大文字小文字・コメントの違いを吸収する。
+func Fingerprint(query string) string {
+	q := strings.TrimSpace(query)
+	if strings.HasPrefix(q, "# administrator command:") {
+		return strings.ToLower(q)
+	}
+
+	q = replaceQuoted(q)
+	q = blockCommentRe.ReplaceAllString(q, "")
+	q = lineCommentRe.ReplaceAllString(q, "")
+	q = strings.ToLower(q)
+	q = numberRe.ReplaceAllString(q, "?")
+	q = signedNumberRe.ReplaceAllString(q, "$1?")
+	q = spaceRe.ReplaceAllString(q, " ")
+	q = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(q), ";"))
+	q = inListRe.ReplaceAllString(q, "in (?+)")
+	q = valuesListRe.ReplaceAllString(q, "$1 (?+)")
+	q = limitRe.ReplaceAllString(q, "limit ?")
+
+	return q
+}
+
+// replaceQuoted シングル/ダブルクォートで囲まれた文字列リテラルを ? に置換
+//
+// バッククォートの識別子はそのまま残す。
+func replaceQuoted(q string) string {
+	var b strings.Builder
+	b.Grow(len(q))
+
+	for i := 0; i < len(q); i++ {
+		c := q[i]
+		if c == '`' {
+			end := strings.IndexByte(q[i+1:], '`')
+			if end < 0 {
+				b.WriteString(q[i:])
+				break
+			}
+			b.WriteString(q[i : i+end+2])
+			i += end + 1
+			continue
+		}
+		if c != '\'' && c != '"' {
+			b.WriteByte(c)
+			continue
+		}
+
+		// リテラル終端まで読み飛ばす（\' と '' のエスケープに対応）
+		j := i + 1
+		for j < len(q) {
+			if q[j] == '\\' {
+				j += 2
+				continue
+			}
+			if q[j] == c {
+				if j+1 < len(q) && q[j+1] == c {
+					j += 2
+					continue
+				}
+				break
+			}
+			j++
+		}
+		b.WriteByte('?')
+		i = j
+	}
+
+	return b.String()
+}
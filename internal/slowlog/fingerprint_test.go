@@ -0,0 +1,41 @@
+// internal/slowlog/fingerprint_test.go
+package slowlog
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"数値と文字列", "SELECT * FROM users WHERE id = 42 AND name = 'alice'", "select * from users where id = ? and name = ?"},
+		{"クォートのエスケープ", `SELECT 1 FROM t WHERE a = 'O''Brien' AND b = 'it\'s' AND c = "x"`, "select ? from t where a = ? and b = ? and c = ?"},
+		{"バッククォートの識別子は残す", "SELECT `name` FROM `users` WHERE `users`.`id` = 7", "select `name` from `users` where `users`.`id` = ?"},
+		{"識別子中の数字は残す", "SELECT t1.col2 FROM t1", "select t1.col2 from t1"},
+		{"小数・指数・16進数", "SELECT 1.5, 2e10, 0x1F", "select ?, ?, ?"},
+		{"負の数", "SELECT * FROM t WHERE a = -5 AND b IN (-1, 2)", "select * from t where a = ? and b in (?+)"},
+		{"IN リストの件数", "SELECT * FROM t WHERE id IN (1, 2, 3)", "select * from t where id in (?+)"},
+		{"IN リストの件数（1件）", "SELECT * FROM t WHERE id IN (1)", "select * from t where id in (?+)"},
+		{"VALUES の行数", "INSERT INTO `t` (`a`,`b`) VALUES (1,'x'),(2,'y'),(3,NOW())", "insert into `t` (`a`,`b`) values (?+)"},
+		{"LIMIT と OFFSET", "SELECT * FROM t LIMIT 10 OFFSET 20", "select * from t limit ?"},
+		{"LIMIT の2引数形式", "SELECT * FROM t LIMIT 5, 10", "select * from t limit ?"},
+		{"コメント・空白・セミコロン", "/* app */ SELECT\n\t*  FROM t -- trailing\n WHERE a = 1 ;", "select * from t where a = ?"},
+		{"管理コマンド", "# administrator command: Ping;", "# administrator command: ping;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fingerprint(tt.query); got != tt.want {
+				t.Errorf("Fingerprint(%q) = %q, 期待値 %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFingerprintGroupsLiterals(t *testing.T) {
+	a := Fingerprint("SELECT * FROM `posts` WHERE slug = 'hello-world' LIMIT 1")
+	b := Fingerprint("select *   from `posts`\nwhere slug = 'another'\nlimit 20")
+	if a != b {
+		t.Errorf("リテラルだけが異なるクエリの指紋が一致しません: %q / %q", a, b)
+	}
+}
@@ -0,0 +1,261 @@
+// internal/slowlog/origins.go
+package slowlog
+
+import (
+	"regexp"
+)
+
+// originRule 指紋パターンと発行元メソッドの対応
+type originRule struct {
+	pattern *regexp.Regexp
+	methods []string
+}
+
+// originRules GORM が生成する SQL の指紋から発行元を推定するルール
+//
+// 上から順に評価し、最初に一致したルールを採用する。
+// より限定的なパターンを先に記述すること。
+var originRules = []originRule{
+	// ----------------- users -----------------
+	rule("^select users\\.id, users\\.name, users\\.email, count\\(distinct posts\\.id\\)",
+		"userRepository.ListWithStats"),
+	rule("^select \\* from `users` where `users`\\.`id` = \\? order by `users`\\.`id` limit \\?$",
+		"userRepository.GetByID"),
+	rule("^select \\* from `users` where email = \\?",
+		"userRepository.GetByEmail", "cmd/setup createTestUser"),
+	rule("^select \\* from `users` where lower\\(name\\) like \\?",
+		"userRepository.Search"),
+	rule("^select \\* from `users` where email_verified_at is not null order by created_at desc",
+		"userRepository.GetActiveUsers"),
+	rule("^select \\* from `users` where `users`\\.`id` (= \\?|in \\(\\?\\+\\))$",
+		"Preload(\"User\") (postRepository.*)", "Preload(\"Comments.User\") (postRepository.GetByID)"),
+	rule("^select \\* from `users` order by created_at desc",
+		"userRepository.List"),
+	rule("^select count\\(\\*\\) from `users` where email_verified_at is (not )?null",
+		"userRepository.CountByStatus"),
+	rule("^select count\\(\\*\\) from `users`$",
+		"userRepository.Count"),
+	rule("^select \\* from `users` where email_verification_token = \\?",
+		"userRepository.GetByVerificationToken (account.Service.VerifyEmail)"),
+	rule("^update `users` set `email_verification_expires_at`=\\?,`email_verification_token`=\\?",
+		"userRepository.SetVerificationToken (account.Service.IssueVerificationToken)"),
+	rule("^update `users` set `email_verification_expires_at`=null,`email_verification_token`=null,`email_verified_at`=\\?",
+		"userRepository.MarkEmailVerified (account.Service.VerifyEmail)"),
+	rule("^select \\* from `users` where remember_token = \\?",
+		"userRepository.GetByRememberToken (account.Service.ConsumeRememberToken)"),
+	rule("^update `users` set `remember_token`=\\?,`remember_token_expires_at`=\\?,`updated_at`=\\? where id = \\? and remember_token = \\?",
+		"userRepository.RotateRememberToken (account.Service.ConsumeRememberToken)"),
+	rule("^update `users` set `remember_token`=(\\?|null),`remember_token_expires_at`=(\\?|null)",
+		"userRepository.SetRememberToken (account.Service.IssueRememberToken/ForgetRememberToken)"),
+	rule("^update `users` set `password`=\\? where id = \\? and password = \\?",
+		"User.CheckPassword (rehash)"),
+	rule("^update `users` set",
+		"userRepository.Update"),
+	rule("^delete from `users` where `users`\\.`id` = \\?",
+		"userRepository.Delete"),
+	rule("^insert into `users`",
+		"userRepository.Create", "DataGenerator.GenerateUsers"),
+
+	// ----------------- posts -----------------
+	rule("^select `posts`\\.`id`.* from `posts` join post_tags on posts\\.id = post_tags\\.post_id where post_tags\\.tag_id = \\?",
+		"postRepository.ListByTag"),
+	rule("^select \\* from `posts` where `posts`\\.`id` = \\? order by `posts`\\.`id` limit \\?$",
+		"postRepository.GetByID", "postRepository.GetBySlug", "postRepository.Update", "postRepository.AddTags", "postRepository.RemoveTags"),
+	rule("^select \\* from `posts` where slug = \\?",
+		"postRepository.GetBySlug"),
+	rule("^select \\* from `posts` where user_id = \\? order by created_at desc",
+		"postRepository.ListByUser"),
+	rule("^select \\* from `posts` where status = \\? order by view_count desc",
+		"postRepository.GetPopularPosts"),
+	rule("^select \\* from `posts` where status = \\? order by created_at desc",
+		"postRepository.ListByStatus", "postRepository.GetRecentPosts"),
+	rule("^select \\* from `posts` where lower\\(title\\) like \\?",
+		"postRepository.Search"),
+	rule("^select \\* from `posts` where created_at between \\? and \\?",
+		"postRepository.GetPostsByDateRange"),
+	rule("^select \\* from `posts` order by created_at desc",
+		"postRepository.List"),
+	rule("^select \\* from `posts`$",
+		"DataGenerator.AssignTagsToPosts"),
+	rule("^select count\\(\\*\\) from `posts` where user_id = \\?",
+		"postRepository.CountByUser"),
+	rule("^select count\\(\\*\\) from `posts` where status = \\?",
+		"postRepository.CountByStatus"),
+	rule("^select count\\(\\*\\) from `posts`$",
+		"postRepository.Count"),
+	rule("^update `posts` set `view_count`=view_count \\+ \\?",
+		"postRepository.UpdateViewCount", "Post.IncrementViewCount"),
+	rule("^update `posts` set",
+		"postRepository.Update"),
+	rule("^delete from `posts` where `posts`\\.`id` = \\?",
+		"postRepository.Delete"),
+	rule("^insert into `posts`",
+		"postRepository.Create", "DataGenerator.GeneratePosts"),
+
+	// ----------------- tags / post_tags -----------------
+	rule("^select \\* from `post_tags` where `post_tags`\\.`post_id` (= \\?|in \\(\\?\\+\\))",
+		"Preload(\"Tags\") (postRepository.*)"),
+	rule("^select \\* from `tags` where `tags`\\.`id` (= \\?|in \\(\\?\\+\\))",
+		"Preload(\"Tags\") (postRepository.*)", "postRepository.AddTags", "postRepository.RemoveTags", "postRepository.Update",
+		"tagRepository.GetByID", "tagRepository.GetBySlug", "tagRepository.Update"),
+	rule("^select \\* from `tags` where slug = \\?",
+		"tagRepository.GetBySlug"),
+	rule("^select \\* from `tags` where is_active = \\? order by post_count desc",
+		"tagRepository.GetPopularTags"),
+	rule("^select \\* from `tags` order by name",
+		"tagRepository.List"),
+	rule("^select count\\(\\*\\) from `tags`$",
+		"tagRepository.Count"),
+	rule("^select `name` from `tags`",
+		"DataGenerator.GenerateTags"),
+	rule("^select count\\(\\*\\) from `post_tags` where tag_id = \\?",
+		"Tag.UpdatePostCount"),
+	rule("^update `tags` set `post_count`=",
+		"Tag.UpdatePostCount"),
+	rule("^update `tags` t left join",
+		"DataGenerator.updateTagPostCounts"),
+	rule("^update `tags` set",
+		"tagRepository.Update"),
+	rule("^delete from `tags` where `tags`\\.`id` = \\?",
+		"tagRepository.Delete"),
+	rule("^insert into `tags`",
+		"DataGenerator.GenerateTags", "association save (postRepository.AddTags/Update)"),
+	rule("^insert ignore into `post_tags`",
+		"DataGenerator.AssignTagsToPosts"),
+	rule("^insert into `post_tags`",
+		"association Append/Replace (postRepository.AddTags/Update)"),
+	rule("^delete from `post_tags` where `post_tags`\\.",
+		"association Delete/Replace (postRepository.RemoveTags/Update)"),
+
+	// ----------------- comments -----------------
+	rule("^select \\* from `comments` where `comments`\\.`post_id` (= \\?|in \\(\\?\\+\\))",
+		"Preload(\"Comments.User\") (postRepository.GetByID)"),
+	rule("^select count\\(\\*\\) from `comments` where parent_id = \\? and status = \\?",
+		"Comment.CountReplies"),
+	rule("^select \\* from `comments` where `comments`\\.`id` = \\? order by `comments`\\.`id` limit \\?$",
+		"Comment.GetDepth"),
+	rule("^update `comments` set `status`=\\?",
+		"models.ModerateComments (Comment.Moderate, commentRepository.ApprovePending/RejectPending)"),
+	rule("^update `comments` set `body`=\\?",
+		"commentRepository.Update (Comment.BeforeUpdate)"),
+	rule("^select count\\(\\*\\) from `comments` where (ip_address|user_id) = \\? and created_at >= \\?",
+		"moderation.rateRule (Comment.BeforeCreate)"),
+	rule("^select `id` from `comments` where content_hash = \\?",
+		"moderation.duplicateRule (Comment.BeforeCreate)"),
+	rule("^select count\\(\\*\\) as total, coalesce\\(sum\\(status = \\?\\), \\?\\) as spam from \\(select status from `comments`",
+		"moderation.reputationRule (Comment.BeforeCreate)"),
+	rule("^insert into `comments`",
+		"DataGenerator.GenerateComments"),
+	rule("^select `id` from `posts`$",
+		"DataGenerator.GenerateComments"),
+
+	// ----------------- モデレーション -----------------
+	rule("^select `id` from `comments` where id in \\(\\?\\+\\) and status = \\? order by id for update$",
+		"models.ModerateComments (Comment.Moderate, commentRepository.ApprovePending/RejectPending)"),
+	rule("^insert into `moderation_actions`",
+		"models.ModerateComments (Comment.Moderate, commentRepository.ApprovePending/RejectPending)"),
+	rule("^select `id`,`status` from `comments` where `comments`\\.`id` = \\?",
+		"commentRepository.Moderate"),
+	rule("^select \\* from `comments` where status = \\? order by created_at, id",
+		"commentRepository.ListPending"),
+	rule("^select \\* from `moderation_actions` where comment_id = \\?",
+		"commentRepository.ListModerationActions"),
+	rule("^select \\* from `moderation_actions` where moderator_id = \\?",
+		"commentRepository.ListActionsByModerator"),
+	rule("^select ma\\.moderator_id, u\\.name as moderator_name",
+		"commentRepository.GetModeratorActivity"),
+	rule("^(delete from `moderation_actions` where reason = \\?|select `id`,`status` from `comments` order by id limit \\?)",
+		"benchmark moderation (cmd/benchmark)"),
+
+	// ----------------- コメント活動統計 -----------------
+	rule("^select a\\.user_id, u\\.name as user_name, .* from \\(select user_id, (count\\(\\*\\)|sum\\(comment_count\\)) as comment_count, .* limit \\?\\) as a join users u",
+		"commentRepository.GetTopCommenters"),
+	rule("^select a\\.user_id, u\\.name as user_name, .* from \\(select user_id, (count\\(\\*\\)|sum\\(comment_count\\)) as comment_count, .* (and|where) user_id = \\? group by `user_id`\\) as a join users u",
+		"commentRepository.GetUserCommentActivity"),
+	rule("^(delete from `comment_daily_rollups` where day = \\?|insert into comment_daily_rollups )",
+		"commentRepository.RefreshActivityRollup (cmd/maintenance rollup-comments)"),
+	rule("^select min\\(created_at\\) as first, max\\(created_at\\) as last from `comments`",
+		"commentRepository.RefreshActivityRollup (cmd/maintenance rollup-comments)"),
+
+	// ----------------- スレッド -----------------
+	rule("^select \\* from `comments` where id = \\?$",
+		"commentRepository.GetThread (adjacency)"),
+	rule("^select \\* from `comments` where parent_id in \\(\\?\\+\\) order by id$",
+		"commentRepository.GetThread (adjacency)"),
+	rule("^with recursive thread \\(id, depth, sort_key\\)",
+		"commentRepository.GetThread (recursive)"),
+	rule("^select \\*, \\(char_length\\(path\\) - \\?\\) div \\? as depth from `comments` where path like \\?",
+		"commentRepository.GetThread (path)"),
+	rule("^select `path` from `comments` where id = \\?",
+		"commentRepository.GetThread (path)", "commentRepository.GetDepth (path)"),
+	rule("^select `parent_id` from `comments` where id = \\?",
+		"commentRepository.GetDepth (adjacency)"),
+	rule("^with recursive ancestors \\(id, parent_id, depth\\)",
+		"commentRepository.GetDepth (recursive)"),
+	rule("^select parent_id, count\\(\\*\\) as count from `comments` where parent_id in \\(\\?\\+\\) and status = \\? group by `parent_id`",
+		"commentRepository.CountReplies"),
+	rule("^update `comments` set `path`=\\? where `id` = \\?",
+		"Comment.AfterCreate"),
+	rule("^select min\\(id\\) as min_id, max\\(id\\) as max_id from `comments` where parent_id is null",
+		"maintenance.BackfillCommentPaths (cmd/maintenance backfill-comment-paths)"),
+	rule("^update comments c join \\( with recursive tree .* where r\\.parent_id is null and r\\.id between \\? and \\?",
+		"maintenance.BackfillCommentPaths (cmd/maintenance backfill-comment-paths)"),
+	rule("^(select count\\(\\*\\) from `comments` where path = \\?|select `id` from `comments` where parent_id is null and exists)",
+		"benchmark thread (cmd/benchmark)"),
+
+	// ----------------- slug -----------------
+	rule("^select (count\\(\\*\\)|`slug`) from `(posts|tags)` where (id <> \\? and )?slug (= \\?|like \\?)",
+		"slug.Scope.Unique (Post/Tag BeforeCreate, Tag BeforeUpdate)"),
+
+	// ----------------- slug 履歴 -----------------
+	rule("^select `entity_id` from `slug_histories` where entity_type = \\? and slug = \\?",
+		"postRepository.GetBySlug", "tagRepository.GetBySlug"),
+	rule("^(insert into `slug_histories`|delete from `slug_histories` where entity_type = \\? and slug = \\?)",
+		"models.RecordSlugChange (Post/Tag BeforeUpdate)"),
+	rule("^(delete h from|select count\\(\\*\\) from) slug_histories h",
+		"maintenance.PruneSlugHistory (cmd/maintenance)"),
+
+	// ----------------- データ生成 -----------------
+	rule("^select `(email|slug)` from `(users|posts|tags)` where (email|slug) in \\(\\?\\+\\)",
+		"DataGenerator.insertUnique"),
+	rule("^(savepoint|rollback to savepoint) unique_insert$",
+		"DataGenerator.insertUnique"),
+
+	// ----------------- 整合性チェック -----------------
+	rule("^select (count\\(\\*\\)|detail) from \\((select concat|select \\? as detail from dual|with recursive chain)",
+		"integrity.Run (cmd/verify)"),
+	rule("^select id, `[a-z_]+`(, `[a-z_]+`)* from `(users|tags|posts|comments)` order by id$",
+		"integrity.utf8Check (cmd/verify)"),
+	rule("^(delete (c|p|pt) from|update (comments|posts|tags) [cpt] (left )?join|update (posts|comments) set status = \\? where status collate)",
+		"integrity.Run -fix (cmd/verify)"),
+	rule("^((create|drop) temporary table integrity_post_tag_dups|insert into post_tags \\(post_id, tag_id\\) select)",
+		"integrity.Run -fix (cmd/verify)"),
+
+	// ----------------- メンテナンス -----------------
+	rule("^delete from (comment_daily_rollups|moderation_actions|slug_histories|comments|post_tags|posts|tags|users)$",
+		"testdata.Cleanup (delete)"),
+	rule("^alter table (comment_daily_rollups|moderation_actions|slug_histories|comments|post_tags|posts|tags|users) auto_increment = \\?",
+		"testdata.Cleanup (delete)"),
+	rule("^truncate table `(comment_daily_rollups|moderation_actions|slug_histories|comments|post_tags|posts|tags|users|generation_[a-z]+)`$",
+		"testdata.Cleanup (truncate)"),
+	rule("^delete from `(comments|post_tags|posts|tags|users)` where `(id|post_id|tag_id)` between \\? and \\?",
+		"testdata.Cleanup (chunked)"),
+}
+
+// rule originRule を生成
+func rule(pattern string, methods ...string) originRule {
+	return originRule{
+		pattern: regexp.MustCompile(pattern),
+		methods: methods,
+	}
+}
+
+// Origins 指紋から発行元と推定されるリポジトリメソッドを返す
+func Origins(fingerprint string) []string {
+	for _, r := range originRules {
+		if r.pattern.MatchString(fingerprint) {
+			return r.methods
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// internal/slowlog/origins_test.go
+package slowlog
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrigins(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // Origins に含まれるべき発行元
+	}{
+		// users（限定的なルールが汎用のルールより先に一致すること）
+		{"SELECT * FROM `users` WHERE `users`.`id` = 1 ORDER BY `users`.`id` LIMIT 1", "userRepository.GetByID"},
+		{"SELECT * FROM `users` WHERE `users`.`id` IN (1,2,3)", "Preload(\"User\") (postRepository.*)"},
+		{"SELECT * FROM `users` WHERE email = 'a@example.com' ORDER BY `users`.`id` LIMIT 1", "userRepository.GetByEmail"},
+		{"SELECT count(*) FROM `users`", "userRepository.Count"},
+		{"SELECT count(*) FROM `users` WHERE email_verified_at IS NOT NULL", "userRepository.CountByStatus"},
+		{"UPDATE `users` SET `password`='y' WHERE id = 1 AND password = 'x'", "User.CheckPassword (rehash)"},
+		{"UPDATE `users` SET `remember_token`='abc',`remember_token_expires_at`='2026-10-18 00:00:00',`updated_at`='2026-10-18 21:21:24.314' WHERE id = 1 AND remember_token = 'abc'",
+			"userRepository.RotateRememberToken (account.Service.ConsumeRememberToken)"},
+		{"UPDATE `users` SET `remember_token`=NULL,`remember_token_expires_at`=NULL,`updated_at`='2026-10-18 21:21:24.314' WHERE id = 1",
+			"userRepository.SetRememberToken (account.Service.IssueRememberToken/ForgetRememberToken)"},
+		{"UPDATE `users` SET `name`='bob',`updated_at`='2026-10-18 21:21:24.314' WHERE `id` = 1", "userRepository.Update"},
+		{"INSERT INTO `users` (`name`,`email`) VALUES ('a','a@example.com'),('b','b@example.com')", "DataGenerator.GenerateUsers"},
+
+		// posts / tags
+		{"SELECT * FROM `posts` WHERE slug = 'hello' ORDER BY `posts`.`id` LIMIT 1", "postRepository.GetBySlug"},
+		{"SELECT * FROM `posts` WHERE status = 'published' ORDER BY view_count DESC LIMIT 10", "postRepository.GetPopularPosts"},
+		{"UPDATE `posts` SET `view_count`=view_count + 1 WHERE id = 3", "postRepository.UpdateViewCount"},
+		{"SELECT * FROM `posts`", "DataGenerator.AssignTagsToPosts"},
+		{"INSERT IGNORE INTO `post_tags` (`post_id`,`tag_id`) VALUES (1,2),(1,3)", "DataGenerator.AssignTagsToPosts"},
+		{"INSERT INTO `post_tags` (`post_id`,`tag_id`) VALUES (1,2) ON DUPLICATE KEY UPDATE `post_id`=`post_id`", "association Append/Replace (postRepository.AddTags/Update)"},
+		{"UPDATE `tags` SET `post_count`=5 WHERE `id` = 2", "Tag.UpdatePostCount"},
+		{"UPDATE `tags` SET `name`='go',`updated_at`='2026-10-18 21:21:24.314' WHERE `id` = 2", "tagRepository.Update"},
+
+		// comments / モデレーション
+		{"SELECT count(*) FROM `comments` WHERE ip_address = '10.0.0.1' AND created_at >= '2026-10-18 00:00:00'", "moderation.rateRule (Comment.BeforeCreate)"},
+		{"SELECT `id` FROM `comments` WHERE id IN (1,2) AND status = 'pending' ORDER BY id FOR UPDATE",
+			"models.ModerateComments (Comment.Moderate, commentRepository.ApprovePending/RejectPending)"},
+		{"UPDATE `comments` SET `path`='00000000001/' WHERE `id` = 1", "Comment.AfterCreate"},
+
+		// コメント活動統計（期間の端が日の途中の場合は日別集計と comments を合算する）
+		{"SELECT a.user_id, u.name AS user_name, a.comment_count FROM (SELECT user_id, SUM(comment_count) AS comment_count, MAX(last_comment_at) AS last_comment FROM (SELECT user_id, comment_count FROM `comment_daily_rollups` WHERE day >= '2026-10-17' UNION ALL SELECT user_id, COUNT(*) AS comment_count FROM `comments` WHERE (created_at >= '2026-10-16 05:00:00') GROUP BY `user_id`) AS d GROUP BY `user_id` ORDER BY comment_count DESC, user_id LIMIT 10) AS a JOIN users u ON u.id = a.user_id",
+			"commentRepository.GetTopCommenters"},
+		{"SELECT a.user_id, u.name AS user_name, a.comment_count FROM (SELECT user_id, SUM(comment_count) AS comment_count, MAX(last_comment_at) AS last_comment FROM (SELECT user_id, comment_count FROM `comment_daily_rollups` WHERE day >= '2026-10-17' UNION ALL SELECT user_id, COUNT(*) AS comment_count FROM `comments` WHERE (created_at >= '2026-10-16 05:00:00') GROUP BY `user_id`) AS d WHERE user_id = 3 GROUP BY `user_id`) AS a JOIN users u ON u.id = a.user_id",
+			"commentRepository.GetUserCommentActivity"},
+		{"SELECT a.user_id, u.name AS user_name, a.comment_count FROM (SELECT user_id, COUNT(*) AS comment_count, MAX(created_at) AS last_comment FROM `comments` WHERE created_at >= '2026-10-16' AND created_at < '2026-10-18' AND user_id = 3 GROUP BY `user_id`) AS a JOIN users u ON u.id = a.user_id",
+			"commentRepository.GetUserCommentActivity"},
+
+		// スレッド
+		{"SELECT * FROM `comments` WHERE id = 5", "commentRepository.GetThread (adjacency)"},
+		{"SELECT * FROM `comments` WHERE parent_id IN (5,6) ORDER BY id", "commentRepository.GetThread (adjacency)"},
+		{"WITH RECURSIVE thread (id, depth, sort_key) AS (SELECT id, 0, '' FROM comments WHERE id = 1) SELECT c.*, t.depth FROM comments c JOIN thread t ON t.id = c.id",
+			"commentRepository.GetThread (recursive)"},
+		{"WITH RECURSIVE ancestors (id, parent_id, depth) AS (SELECT id, parent_id, 0 FROM comments WHERE id = 1) SELECT MAX(depth) AS depth FROM ancestors",
+			"commentRepository.GetDepth (recursive)"},
+		{"SELECT `parent_id` FROM `comments` WHERE id = 9", "commentRepository.GetDepth (adjacency)"},
+
+		// slug・データ生成・整合性チェック・メンテナンス
+		{"SELECT count(*) FROM `posts` WHERE slug = 'hello'", "slug.Scope.Unique (Post/Tag BeforeCreate, Tag BeforeUpdate)"},
+		{"SELECT `slug` FROM `posts` WHERE slug IN ('a','b')", "DataGenerator.insertUnique"},
+		{"SAVEPOINT unique_insert", "DataGenerator.insertUnique"},
+		{"SELECT COUNT(*) FROM (SELECT CONCAT('post ', p.id) AS detail FROM posts p) q", "integrity.Run (cmd/verify)"},
+		{"SELECT COUNT(*) FROM (SELECT 'post_tags: x' AS detail FROM DUAL WHERE NOT EXISTS (SELECT 1)) q", "integrity.Run (cmd/verify)"},
+		{"CREATE TEMPORARY TABLE integrity_post_tag_dups AS SELECT post_id, tag_id FROM post_tags", "integrity.Run -fix (cmd/verify)"},
+		{"DELETE FROM post_tags", "testdata.Cleanup (delete)"},
+		{"TRUNCATE TABLE `comments`", "testdata.Cleanup (truncate)"},
+		{"DELETE FROM `posts` WHERE `id` BETWEEN 1 AND 1000", "testdata.Cleanup (chunked)"},
+	}
+	for _, tt := range tests {
+		fp := Fingerprint(tt.query)
+		if got := Origins(fp); !slices.Contains(got, tt.want) {
+			t.Errorf("Origins(%q) = %q, %q が含まれていません", fp, got, tt.want)
+		}
+	}
+}
+
+func TestOriginsUnknown(t *testing.T) {
+	for _, q := range []string{
+		"SELECT 1",
+		"ALTER TABLE post_tags ADD UNIQUE INDEX idx_post_tags_post_tag (post_id, tag_id)",
+		"SELECT * FROM `unknown_table`",
+	} {
+		if got := Origins(Fingerprint(q)); got != nil {
+			t.Errorf("Origins(%q) = %q, 一致しないはずです", q, got)
+		}
+	}
+}
+
+func TestOriginRulesHaveMethods(t *testing.T) {
+	for _, r := range originRules {
+		if len(r.methods) == 0 {
+			t.Errorf("発行元のないルール: %s", r.pattern)
+		}
+	}
+}
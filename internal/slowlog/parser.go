@@ -0,0 +1,202 @@
+// internal/slowlog/parser.go
+package slowlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry スロークエリログの1エントリ
+type Entry struct {
+	Time         time.Time
+	User         string
+	Host         string
+	ThreadID     int64
+	QueryTime    time.Duration
+	LockTime     time.Duration
+	RowsSent     int64
+	RowsExamined int64
+	Database     string
+	Query        string
+}
+
+// Parser MySQL スロークエリログのストリーミングパーサー
+type Parser struct {
+	scanner  *bufio.Scanner
+	database string // 直前の use 文で指定されたデータベース
+	pending  string // 次のエントリの先頭行（先読み分）
+	lineNo   int
+}
+
+// NewParser パーサーを作成
+func NewParser(r io.Reader) *Parser {
+	scanner := bufio.NewScanner(r)
+	// 巨大な INSERT 文にも対応できるようバッファを拡張
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	return &Parser{scanner: scanner}
+}
+
+// Next 次のエントリを返す（終端では io.EOF）
+func (p *Parser) Next() (*Entry, error) {
+	var (
+		entry     *Entry
+		query     strings.Builder
+		inQuery   bool
+		haveStats bool
+	)
+
+	for {
+		line, ok := p.readLine()
+		if !ok {
+			break
+		}
+
+		if strings.HasPrefix(line, "# ") && !(haveStats && strings.HasPrefix(line, "# administrator command:")) {
+			// クエリ本文の後に来たヘッダーは次のエントリの開始
+			if inQuery {
+				p.pending = line
+				break
+			}
+			if entry == nil {
+				entry = &Entry{Database: p.database}
+			}
+			if err := p.parseHeader(entry, line); err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(line, "# Query_time:") {
+				haveStats = true
+			}
+			continue
+		}
+
+		if entry == nil || !haveStats {
+			// ログ先頭のサーバー起動情報などは読み飛ばす
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		lower := strings.ToLower(trimmed)
+		switch {
+		case !inQuery && strings.HasPrefix(lower, "use "):
+			p.database = strings.Trim(strings.TrimSuffix(trimmed[4:], ";"), "` ")
+			entry.Database = p.database
+			continue
+		case !inQuery && strings.HasPrefix(lower, "set timestamp="):
+			continue
+		}
+
+		if inQuery {
+			query.WriteByte('\n')
+		}
+		query.WriteString(line)
+		inQuery = true
+	}
+
+	if err := p.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("スローログ読み込みエラー (%d行目): %w", p.lineNo, err)
+	}
+	if entry == nil || !inQuery {
+		return nil, io.EOF
+	}
+
+	entry.Query = strings.TrimSpace(query.String())
+	return entry, nil
+}
+
+// readLine 先読み行を優先して1行読み込む
+func (p *Parser) readLine() (string, bool) {
+	if p.pending != "" {
+		line := p.pending
+		p.pending = ""
+		return line, true
+	}
+	if !p.scanner.Scan() {
+		return "", false
+	}
+	p.lineNo++
+	return p.scanner.Text(), true
+}
+
+// parseHeader "# Key: value" 形式のヘッダー行を解析
+func (p *Parser) parseHeader(entry *Entry, line string) error {
+	body := strings.TrimPrefix(line, "# ")
+
+	switch {
+	case strings.HasPrefix(body, "Time:"):
+		value := strings.TrimSpace(strings.TrimPrefix(body, "Time:"))
+		t, err := parseLogTime(value)
+		if err != nil {
+			return fmt.Errorf("時刻の解析エラー (%d行目): %w", p.lineNo, err)
+		}
+		entry.Time = t
+
+	case strings.HasPrefix(body, "User@Host:"):
+		// 例: root[root] @ localhost [127.0.0.1]  Id:     8
+		value := strings.TrimSpace(strings.TrimPrefix(body, "User@Host:"))
+		if idx := strings.Index(value, "Id:"); idx >= 0 {
+			id, err := strconv.ParseInt(strings.TrimSpace(value[idx+3:]), 10, 64)
+			if err == nil {
+				entry.ThreadID = id
+			}
+			value = strings.TrimSpace(value[:idx])
+		}
+		userPart, hostPart, _ := strings.Cut(value, "@")
+		if i := strings.Index(userPart, "["); i >= 0 {
+			userPart = userPart[:i]
+		}
+		entry.User = strings.TrimSpace(userPart)
+		entry.Host = strings.Join(strings.Fields(hostPart), " ")
+
+	case strings.HasPrefix(body, "Query_time:"):
+		// 例: Query_time: 2.000123  Lock_time: 0.000001 Rows_sent: 10  Rows_examined: 100000
+		fields := strings.Fields(body)
+		for i := 0; i+1 < len(fields); i += 2 {
+			key := strings.TrimSuffix(fields[i], ":")
+			value := fields[i+1]
+			var err error
+			switch key {
+			case "Query_time":
+				entry.QueryTime, err = parseSeconds(value)
+			case "Lock_time":
+				entry.LockTime, err = parseSeconds(value)
+			case "Rows_sent":
+				entry.RowsSent, err = strconv.ParseInt(value, 10, 64)
+			case "Rows_examined":
+				entry.RowsExamined, err = strconv.ParseInt(value, 10, 64)
+			}
+			if err != nil {
+				return fmt.Errorf("%s の解析エラー (%d行目): %w", key, p.lineNo, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseLogTime MySQL 8 (RFC3339) と 5.x (YYMMDD HH:MM:SS) の時刻形式に対応
+func parseLogTime(value string) (time.Time, error) {
+	layouts := []string{
+		time.RFC3339Nano,
+		"060102 15:04:05",
+		"060102  15:04:05",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("未対応の時刻形式: %q", value)
+}
+
+// parseSeconds 秒数の文字列を time.Duration に変換
+func parseSeconds(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,77 @@
+// internal/slowlog/parser_test.go
+package slowlog
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleLog = `/usr/sbin/mysqld, Version: 8.0.36 (MySQL Community Server - GPL). started with:
+Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+Time                 Id Command    Argument
+# Time: 2026-10-18T12:00:00.123456Z
+# User@Host: app[app] @ localhost [127.0.0.1]  Id:     8
+# Query_time: 2.500000  Lock_time: 0.000100 Rows_sent: 10  Rows_examined: 100000
+use blog;
+SET timestamp=1760788800;
+SELECT *
+FROM posts
+WHERE status = 'published';
+# Time: 2026-10-18T12:00:01.000000Z
+# User@Host: app[app] @  [10.0.0.2]  Id:     9
+# Query_time: 0.250000  Lock_time: 0.000000 Rows_sent: 0  Rows_examined: 0
+SET timestamp=1760788801;
+# administrator command: Ping;
+`
+
+func TestParser(t *testing.T) {
+	p := NewParser(strings.NewReader(sampleLog))
+
+	first, err := p.Next()
+	if err != nil {
+		t.Fatalf("1件目の解析エラー: %v", err)
+	}
+	if want := time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC); !first.Time.Equal(want) {
+		t.Errorf("Time = %v, 期待値 %v", first.Time, want)
+	}
+	if first.User != "app" || first.Host != "localhost [127.0.0.1]" || first.ThreadID != 8 {
+		t.Errorf("User/Host/ThreadID = %q/%q/%d", first.User, first.Host, first.ThreadID)
+	}
+	if first.QueryTime != 2500*time.Millisecond || first.LockTime != 100*time.Microsecond {
+		t.Errorf("QueryTime/LockTime = %v/%v", first.QueryTime, first.LockTime)
+	}
+	if first.RowsSent != 10 || first.RowsExamined != 100000 {
+		t.Errorf("RowsSent/RowsExamined = %d/%d", first.RowsSent, first.RowsExamined)
+	}
+	if first.Database != "blog" {
+		t.Errorf("Database = %q, 期待値 %q", first.Database, "blog")
+	}
+	if want := "SELECT *\nFROM posts\nWHERE status = 'published';"; first.Query != want {
+		t.Errorf("Query = %q, 期待値 %q", first.Query, want)
+	}
+
+	second, err := p.Next()
+	if err != nil {
+		t.Fatalf("2件目の解析エラー: %v", err)
+	}
+	if second.Query != "# administrator command: Ping;" {
+		t.Errorf("Query = %q", second.Query)
+	}
+	if second.Database != "blog" {
+		t.Errorf("直前の use 文のデータベースを引き継いでいません: %q", second.Database)
+	}
+
+	if _, err := p.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("終端で io.EOF になりません: %v", err)
+	}
+}
+
+func TestParserInvalidHeader(t *testing.T) {
+	p := NewParser(strings.NewReader("# Time: yesterday\n# Query_time: 1\nSELECT 1;\n"))
+	if _, err := p.Next(); err == nil {
+		t.Error("未対応の時刻形式でエラーになりません")
+	}
+}
@@ -0,0 +1,143 @@
+// internal/slowlog/report.go
+package slowlog
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// QueryStats 指紋ごとの集計結果
+type QueryStats struct {
+	Fingerprint     string        `json:"fingerprint"`
+	Origins         []string      `json:"origins,omitempty"`
+	Count           int           `json:"count"`
+	TotalTime       time.Duration `json:"total_time"`
+	AvgTime         time.Duration `json:"avg_time"`
+	P95Time         time.Duration `json:"p95_time"`
+	MaxTime         time.Duration `json:"max_time"`
+	TotalLockTime   time.Duration `json:"total_lock_time"`
+	RowsExamined    int64         `json:"rows_examined"`
+	RowsSent        int64         `json:"rows_sent"`
+	ExaminedPerSent float64       `json:"examined_per_sent"` // 送信1行あたりの走査行数
+	FirstSeen       time.Time     `json:"first_seen"`
+	LastSeen        time.Time     `json:"last_seen"`
+	Example         string        `json:"example"`
+
+	durations []time.Duration
+}
+
+// Aggregator 指紋ごとにエントリを集計
+type Aggregator struct {
+	stats map[string]*QueryStats
+}
+
+// NewAggregator 集計器を作成
+func NewAggregator() *Aggregator {
+	return &Aggregator{stats: make(map[string]*QueryStats)}
+}
+
+// Add エントリを集計に追加
+func (a *Aggregator) Add(e *Entry) {
+	fp := Fingerprint(e.Query)
+
+	s, ok := a.stats[fp]
+	if !ok {
+		s = &QueryStats{
+			Fingerprint: fp,
+			Origins:     Origins(fp),
+			Example:     e.Query,
+			FirstSeen:   e.Time,
+		}
+		a.stats[fp] = s
+	}
+
+	s.Count++
+	s.TotalTime += e.QueryTime
+	s.TotalLockTime += e.LockTime
+	s.RowsExamined += e.RowsExamined
+	s.RowsSent += e.RowsSent
+	s.durations = append(s.durations, e.QueryTime)
+
+	if e.QueryTime > s.MaxTime {
+		s.MaxTime = e.QueryTime
+		s.Example = e.Query // 最も遅い実例を残す
+	}
+	if !e.Time.IsZero() {
+		if s.FirstSeen.IsZero() || e.Time.Before(s.FirstSeen) {
+			s.FirstSeen = e.Time
+		}
+		if e.Time.After(s.LastSeen) {
+			s.LastSeen = e.Time
+		}
+	}
+}
+
+// SortKey 集計結果の並び順
+type SortKey string
+
+const (
+	SortByTotal    SortKey = "total"
+	SortByCount    SortKey = "count"
+	SortByAvg      SortKey = "avg"
+	SortByP95      SortKey = "p95"
+	SortByExamined SortKey = "examined"
+)
+
+// Results 集計結果を指定順（降順）で返す
+func (a *Aggregator) Results(sortKey SortKey) []QueryStats {
+	results := make([]QueryStats, 0, len(a.stats))
+	for _, s := range a.stats {
+		s.AvgTime = s.TotalTime / time.Duration(s.Count)
+		s.P95Time = percentile(s.durations, 0.95)
+		if s.RowsSent > 0 {
+			s.ExaminedPerSent = float64(s.RowsExamined) / float64(s.RowsSent)
+		} else {
+			s.ExaminedPerSent = float64(s.RowsExamined)
+		}
+		results = append(results, *s)
+	}
+
+	less := func(x, y QueryStats) bool {
+		switch sortKey {
+		case SortByCount:
+			return x.Count > y.Count
+		case SortByAvg:
+			return x.AvgTime > y.AvgTime
+		case SortByP95:
+			return x.P95Time > y.P95Time
+		case SortByExamined:
+			return x.RowsExamined > y.RowsExamined
+		default:
+			return x.TotalTime > y.TotalTime
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if less(results[i], results[j]) {
+			return true
+		}
+		if less(results[j], results[i]) {
+			return false
+		}
+		return results[i].Fingerprint < results[j].Fingerprint
+	})
+
+	return results
+}
+
+// percentile 最近傍法でパーセンタイル値を計算
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,146 @@
+// internal/slug/romaji.go
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// digraphs 2文字で1音になるかな（拗音・外来語の表記）
+var digraphs = map[string]string{
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "でゅ": "dyu", "とぅ": "tu", "どぅ": "du",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+}
+
+// monographs 1文字のかな
+var monographs = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+	'ゕ': "ka", 'ゖ': "ke",
+}
+
+// katakanaOffset カタカナとひらがなのコードポイントの差
+const katakanaOffset = 'ア' - 'あ'
+
+// toHiragana カタカナをひらがなに変換（それ以外はそのまま）
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - katakanaOffset
+	}
+	return r
+}
+
+// segment かなの文字種の境目に空白を入れて語に分ける（Go言語でWebアプリ → go言語で web アプリ）
+func segment(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, r := range runes {
+		if i > 0 && wordBoundary(runes[i-1], r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// romanize 語の中のかなをローマ字に変換（かな以外はそのまま）
+//
+// 促音（っ）は次の子音を重ね、長音符（ー）は省略する（データベース → detabesu）。
+func romanize(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	geminate := false
+	for i := 0; i < len(runes); i++ {
+		r := toHiragana(runes[i])
+		if r == 'っ' {
+			geminate = true
+			continue
+		}
+		if r == 'ー' {
+			continue
+		}
+
+		var roman string
+		if i+1 < len(runes) {
+			roman = digraphs[string([]rune{r, toHiragana(runes[i+1])})]
+		}
+		if roman != "" {
+			i++
+		} else if m, ok := monographs[r]; ok {
+			roman = m
+		} else {
+			b.WriteRune(runes[i])
+			continue
+		}
+
+		if geminate {
+			if strings.HasPrefix(roman, "ch") {
+				b.WriteByte('t')
+			} else if c := roman[0]; !strings.ContainsRune("aiueon", rune(c)) {
+				b.WriteByte(c)
+			}
+			geminate = false
+		}
+		b.WriteString(roman)
+	}
+	if geminate {
+		b.WriteString("tsu") // 語末の単独の促音
+	}
+	return b.String()
+}
+
+// script かなの文字種（かな以外は 0）
+func script(r rune) int {
+	switch {
+	case r >= 'ぁ' && r <= 'ゖ':
+		return 1
+	case (r >= 'ァ' && r <= 'ヶ') || r == 'ー':
+		return 2
+	}
+	return 0
+}
+
+// wordBoundary prev と r の間で語を区切るか
+//
+// ひらがな・カタカナ・それ以外が切り替わる位置で区切る。
+// ただし漢字に続くひらがなは送り仮名として同じ語にする（教える はそのまま1語）。
+func wordBoundary(prev, r rune) bool {
+	ps, rs := script(prev), script(r)
+	if ps == rs {
+		return false
+	}
+	return !(rs == 1 && unicode.Is(unicode.Han, prev))
+}
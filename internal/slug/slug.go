@@ -0,0 +1,115 @@
+// internal/slug/slug.go
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Separator 語の区切り文字
+const Separator = '-'
+
+// Make タイトルや名前から slug を作る
+//
+// 1. NFKC 正規化（全角英数字・半角カナ・合字などを標準の文字に揃える）し、小文字化
+// 2. 文字・数字以外と、かなの文字種の境目で語に分ける
+// 3. 語ごとに、ひらがな・カタカナをヘボン式のローマ字にし、ラテン文字のアクセント記号を除去（café → cafe）
+// 4. 語を区切り文字でつなぐ
+//
+// 漢字を含む語など ASCII にできない語は、ローマ字を混ぜずに元の表記のまま残す
+// （Go言語入門 ガイド → go言語入門-gaido。URL ではパーセントエンコードされる）。
+// maxLength は文字数の上限で、0 以下なら切り詰めない。変換後に何も残らなければ空文字を返す。
+func Make(s string, maxLength int) string {
+	return join(words(s), false, maxLength)
+}
+
+// ASCII Make と同じ規則で、ASCII にできない語を除いた slug を作る
+//
+// URL にそのまま使える値が必要な場合（テストデータなど）に使う。
+func ASCII(s string, maxLength int) string {
+	return join(words(s), true, maxLength)
+}
+
+// words 正規化して語に分ける（アポストロフィは除く。don't → dont）
+func words(s string) []string {
+	s = segment(strings.ToLower(norm.NFKC.String(s)))
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) ||
+			r == '\'' || r == '’')
+	})
+	for i, f := range fields {
+		fields[i] = strings.NewReplacer("'", "", "’", "").Replace(f)
+	}
+	return fields
+}
+
+// join 語を ASCII に変換して区切り文字でつなぐ（変換できない語は asciiOnly なら除き、そうでなければ元の表記を使う）
+func join(words []string, asciiOnly bool, maxLength int) string {
+	var b strings.Builder
+	for _, w := range words {
+		if a, ok := transliterate(w); ok {
+			w = a
+		} else if asciiOnly {
+			continue
+		} else {
+			w = norm.NFC.String(w)
+		}
+		if w == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteRune(Separator)
+		}
+		b.WriteString(w)
+	}
+	return Truncate(b.String(), maxLength)
+}
+
+// transliterate 1語をローマ字化してアクセント記号を除き、ASCII だけになれば返す
+func transliterate(word string) (string, bool) {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(romanize(word)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r >= utf8.RuneSelf {
+			return "", false
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), true
+}
+
+// Truncate maxLength 文字に切り詰める
+//
+// できるだけ語の区切りで切り、末尾に区切り文字を残さない。
+func Truncate(s string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+	runes := []rune(s)
+	cut := string(runes[:maxLength])
+	// 語の途中で切れる場合は直前の区切りまで戻す（半分以上戻る場合はそのまま）
+	if runes[maxLength] != Separator {
+		if i := strings.LastIndexByte(cut, Separator); i > len(cut)/2 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, string(Separator))
+}
+
+// WithSuffix 重複を避けるための連番を付ける（foo, 2 → foo-2）
+//
+// 連番を付けても maxLength 文字に収まるよう、元の slug の末尾を文字単位で切り詰める
+// （語の区切りまでは戻さない。Scope.Unique が接頭辞で重複を確認するため）。
+func WithSuffix(s string, n, maxLength int) string {
+	suffix := string(Separator) + strconv.Itoa(n)
+	if runes := []rune(s); maxLength > 0 && len(runes)+len(suffix) > maxLength {
+		s = strings.TrimRight(string(runes[:maxLength-len(suffix)]), string(Separator))
+	}
+	return s + suffix
+}
@@ -0,0 +1,101 @@
+// internal/slug/unique.go
+package slug
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Scope slug を一意にする範囲（テーブルと列）
+type Scope struct {
+	Table     string
+	Column    string
+	MaxLength int    // 列の長さ（文字数）
+	Fallback  string // タイトル・名前から slug が作れない場合に使う語
+}
+
+// maxSuffixLength 連番の最大の長さ（"-" と5桁）
+const maxSuffixLength = 6
+
+// 各テーブルの slug 列
+var (
+	Posts = Scope{Table: "posts", Column: "slug", MaxLength: 255, Fallback: "post"}
+	Tags  = Scope{Table: "tags", Column: "slug", MaxLength: 100, Fallback: "tag"}
+)
+
+// Make 列の長さに収まる slug を作る（作れない場合は Fallback）
+func (s Scope) Make(text string) string {
+	if slug := Make(text, s.MaxLength); slug != "" {
+		return slug
+	}
+	return s.Fallback
+}
+
+// MakeASCII 列の長さに収まる ASCII だけの slug を作る（作れない場合は Fallback）
+func (s Scope) MakeASCII(text string) string {
+	if slug := ASCII(text, s.MaxLength); slug != "" {
+		return slug
+	}
+	return s.Fallback
+}
+
+// Unique base が未使用ならそのまま、使用済みなら -2, -3, ... の空いている最小の連番を付けて返す
+//
+// 作成・更新と同じトランザクション（フックに渡される tx）で呼ぶこと。
+// excludeID は更新時の自分自身の行の ID（作成時は 0）。
+// 同時に同じ slug で作成された場合は一意インデックスの違反になるため、呼び出し側でやり直す。
+func (s Scope) Unique(tx *gorm.DB, base string, excludeID uint) (string, error) {
+	base = Truncate(base, s.MaxLength)
+	if base == "" {
+		base = s.Fallback
+	}
+
+	// ほとんどの場合は重複しないため、まず base だけを一意インデックスで確認する
+	var count int64
+	if err := s.query(tx, excludeID).Where(fmt.Sprintf("%s = ?", s.Column), base).Count(&count).Error; err != nil {
+		return "", fmt.Errorf("%s.%s の重複確認エラー: %w", s.Table, s.Column, err)
+	}
+	if count == 0 {
+		return base, nil
+	}
+
+	// 連番付きの slug を取得する。連番を付けると base が切り詰められることがあるため、
+	// 長い base は切り詰めても変わらない接頭辞で検索する
+	pattern := likePrefix(base) + "-%"
+	if n := s.MaxLength - maxSuffixLength; utf8.RuneCountInString(base) > n {
+		pattern = likePrefix(string([]rune(base)[:n])) + "%"
+	}
+	var existing []string
+	if err := s.query(tx, excludeID).Where(fmt.Sprintf("%s LIKE ?", s.Column), pattern).Pluck(s.Column, &existing).Error; err != nil {
+		return "", fmt.Errorf("%s.%s の重複確認エラー: %w", s.Table, s.Column, err)
+	}
+
+	// 照合順序は大文字小文字を区別しないため、小文字に揃えて比較する
+	taken := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		taken[strings.ToLower(e)] = true
+	}
+	for n := 2; ; n++ {
+		candidate := WithSuffix(base, n, s.MaxLength)
+		if !taken[strings.ToLower(candidate)] {
+			return candidate, nil
+		}
+	}
+}
+
+// query 自分自身の行を除いた検索
+func (s Scope) query(tx *gorm.DB, excludeID uint) *gorm.DB {
+	q := tx.Session(&gorm.Session{NewDB: true}).Table(s.Table)
+	if excludeID != 0 {
+		q = q.Where("id <> ?", excludeID)
+	}
+	return q
+}
+
+// likePrefix LIKE の特殊文字をエスケープ
+func likePrefix(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,185 @@
+// internal/snapshot/export.go
+package snapshot
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-db-performance-study/internal/bulkload"
+	"go-db-performance-study/internal/testdata"
+
+	"gorm.io/gorm"
+)
+
+// ExportOptions エクスポートの設定
+type ExportOptions struct {
+	// Force 既存のスナップショットを上書きする
+	Force bool
+	// Level gzip の圧縮レベル（0 の場合は既定値）
+	Level int
+}
+
+// Export 全テーブルをスナップショットとして dir に書き出す
+//
+// 1つの読み取り専用トランザクション（REPEATABLE READ）の中で全テーブルを読むため、
+// 書き込み中のデータベースからでもテーブル間で整合したスナップショットになる。
+// マニフェストは最後に書くので、途中で失敗したディレクトリはインポートできない。
+func Export(db *gorm.DB, dir string, opts ExportOptions) (*Manifest, error) {
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil && !opts.Force {
+		return nil, fmt.Errorf("%s には既にスナップショットがあります（上書きする場合は -force を指定）", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ディレクトリ作成エラー: %w", err)
+	}
+	// 上書き時は古いマニフェストを先に消し、書き出し途中のスナップショットを読み込めないようにする
+	if err := os.Remove(filepath.Join(dir, ManifestFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("マニフェスト削除エラー: %w", err)
+	}
+
+	level := opts.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	manifest := &Manifest{
+		Version:   FormatVersion,
+		Format:    fileFormat,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT DATABASE()").Scan(&manifest.Database).Error; err != nil {
+			return fmt.Errorf("データベース名取得エラー: %w", err)
+		}
+
+		loc := bulkload.ConnLocation(tx)
+		for _, t := range tables {
+			start := time.Now()
+			entry, err := exportTable(tx, dir, t, loc, level)
+			if err != nil {
+				return err
+			}
+			log.Printf("%s: %d行 → %s (%.1f MB, %v)",
+				t.Name, entry.Rows, entry.File, float64(entry.Bytes)/1024/1024, time.Since(start).Round(time.Millisecond))
+			manifest.Tables = append(manifest.Tables, *entry)
+		}
+
+		log.Printf("チェックサムを計算中...")
+		checksum, err := testdata.ComputeChecksum(tx)
+		if err != nil {
+			return fmt.Errorf("チェックサム計算エラー: %w", err)
+		}
+		manifest.Dataset = checksum
+
+		manifest.Generator, err = latestGeneration(tx)
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// exportTable 1テーブルを主キー順に CSV へ書き出し、gzip 圧縮して保存
+func exportTable(tx *gorm.DB, dir string, t table, loc *time.Location, level int) (*TableEntry, error) {
+	entry := &TableEntry{Table: t.Name, File: t.Name + ".csv.gz"}
+
+	rows, err := tx.Raw(fmt.Sprintf("SELECT * FROM `%s` ORDER BY %s", t.Name, t.OrderBy)).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("%s の読み込みエラー: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	entry.Columns, err = rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("%s の列取得エラー: %w", t.Name, err)
+	}
+
+	path := filepath.Join(dir, entry.File)
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイル作成エラー: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewWriterLevel(f, level)
+	if err != nil {
+		return nil, fmt.Errorf("圧縮レベルの指定エラー: %w", err)
+	}
+	h := sha256.New()
+	w := bulkload.NewWriter(io.MultiWriter(gz, h), loc)
+
+	values := make([]interface{}, len(entry.Columns))
+	pointers := make([]interface{}, len(entry.Columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("%s の読み込みエラー: %w", t.Name, err)
+		}
+		if err := w.WriteRow(values); err != nil {
+			return nil, fmt.Errorf("%s の書き出しエラー: %w", t.Name, err)
+		}
+		entry.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s の読み込みエラー: %w", t.Name, err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("%s の書き出しエラー: %w", t.Name, err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("%s の圧縮エラー: %w", t.Name, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイル情報取得エラー: %w", err)
+	}
+	entry.Bytes = info.Size()
+	entry.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return entry, nil
+}
+
+// latestGeneration 最後に完了したデータ生成の設定を取得（記録がなければ nil）
+func latestGeneration(tx *gorm.DB) (*GeneratorInfo, error) {
+	if !tx.Migrator().HasTable(&testdata.GenerationRun{}) {
+		return nil, nil
+	}
+
+	var run testdata.GenerationRun
+	err := tx.Where("status = ?", testdata.RunStatusCompleted).Order("id DESC").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("実行履歴取得エラー: %w", err)
+	}
+
+	return &GeneratorInfo{
+		RunID:    run.ID,
+		Scenario: run.Scenario,
+		Seed:     run.Seed,
+		BaseTime: run.BaseTime,
+		Config:   json.RawMessage(run.Config),
+	}, nil
+}
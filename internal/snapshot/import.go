@@ -0,0 +1,165 @@
+// internal/snapshot/import.go
+package snapshot
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-db-performance-study/internal/bulkload"
+	"go-db-performance-study/internal/testdata"
+
+	"gorm.io/gorm"
+)
+
+// ImportOptions インポートの設定
+type ImportOptions struct {
+	// Replace 既存データを削除してから読み込む（false の場合、データがあればエラー）
+	Replace bool
+	// DisableIndexes 読み込み中はセカンダリインデックスを削除し、最後に作り直す
+	DisableIndexes bool
+	// SkipVerify 読み込み後のデータセットチェックサムの照合を省略する
+	SkipVerify bool
+}
+
+// Import スナップショットを LOAD DATA LOCAL INFILE で読み込む
+//
+// テーブルごとに1トランザクションで読み込み、ファイルの SHA-256 か行数が
+// マニフェストと一致しなければロールバックする。
+// 読み込んだデータは生成の途中状態と対応しないため、チェックポイントは削除する。
+func Import(db *gorm.DB, dir string, opts ImportOptions) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTarget(db, dir, manifest); err != nil {
+		return nil, err
+	}
+
+	if err := prepareTables(db, opts.Replace); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range manifest.Tables {
+		start := time.Now()
+		if err := importTable(db, dir, entry, opts.DisableIndexes); err != nil {
+			return nil, err
+		}
+		log.Printf("%s: %d行を読み込みました (%v)", entry.Table, entry.Rows, time.Since(start).Round(time.Millisecond))
+	}
+
+	if opts.SkipVerify || manifest.Dataset == nil {
+		return manifest, nil
+	}
+
+	log.Printf("チェックサムを照合中...")
+	checksum, err := testdata.ComputeChecksum(db)
+	if err != nil {
+		return nil, fmt.Errorf("チェックサム計算エラー: %w", err)
+	}
+	if !checksum.Equal(manifest.Dataset) {
+		return nil, fmt.Errorf("読み込んだデータのチェックサムがスナップショットと一致しません (%s / %s)",
+			checksum.Checksum, manifest.Dataset.Checksum)
+	}
+	return manifest, nil
+}
+
+// checkTarget スナップショットのテーブル・ファイル・列が揃っているか確認
+func checkTarget(db *gorm.DB, dir string, manifest *Manifest) error {
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t.Name] = true
+	}
+
+	for _, entry := range manifest.Tables {
+		if !known[entry.Table] {
+			return fmt.Errorf("未知のテーブルがスナップショットに含まれています: %s", entry.Table)
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.File)); err != nil {
+			return fmt.Errorf("%s のファイルがありません: %w", entry.Table, err)
+		}
+		if !db.Migrator().HasTable(entry.Table) {
+			return fmt.Errorf("テーブル %s がありません（先にマイグレーションを実行してください）", entry.Table)
+		}
+		for _, column := range entry.Columns {
+			if !db.Migrator().HasColumn(entry.Table, column) {
+				return fmt.Errorf("%s.%s 列がありません（スナップショットとスキーマが一致しません）", entry.Table, column)
+			}
+		}
+	}
+	return nil
+}
+
+// prepareTables 読み込み先のテーブルを空にする（replace が false の場合は空であることを確認）
+func prepareTables(db *gorm.DB, replace bool) error {
+	if !replace {
+		for _, t := range tables {
+			var count int64
+			if err := db.Table(t.Name).Count(&count).Error; err != nil {
+				return fmt.Errorf("テーブル %s の件数取得エラー: %w", t.Name, err)
+			}
+			if count > 0 {
+				return fmt.Errorf("テーブル %s にデータがあります（置き換える場合は -replace を指定）", t.Name)
+			}
+		}
+	}
+
+	// チェックポイントも含めて空にする（読み込むデータとは対応しないため）
+	if _, err := testdata.Cleanup(db, testdata.CleanupOptions{Mode: testdata.CleanupTruncate}); err != nil {
+		return fmt.Errorf("既存データの削除エラー: %w", err)
+	}
+	return nil
+}
+
+// importTable 1テーブル分のファイルを読み込む
+func importTable(db *gorm.DB, dir string, entry TableEntry, disableIndexes bool) (err error) {
+	f, err := os.Open(filepath.Join(dir, entry.File))
+	if err != nil {
+		return fmt.Errorf("ファイル読み込みエラー: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s の展開エラー: %w", entry.File, err)
+	}
+	defer gz.Close()
+
+	if disableIndexes {
+		var restore func() error
+		restore, err = bulkload.DisableIndexes(db, entry.Table)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if restoreErr := restore(); restoreErr != nil && err == nil {
+				err = restoreErr
+			}
+		}()
+	}
+
+	h := sha256.New()
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 親子の順に読み込むが、comments の parent_id のような自己参照もあるため外部キー検査は止める
+		return bulkload.WithoutChecks(tx, func(tx *gorm.DB) error {
+			loaded, err := bulkload.LoadReader(tx, entry.Table, entry.Columns, io.TeeReader(gz, h))
+			if err != nil {
+				return err
+			}
+			if sum := hex.EncodeToString(h.Sum(nil)); sum != entry.SHA256 {
+				return fmt.Errorf("%s の SHA-256 がマニフェストと一致しません（ファイルが壊れている可能性があります）", entry.File)
+			}
+			if loaded != entry.Rows {
+				return fmt.Errorf("%s: 読み込まれたのは %d/%d 行です（重複キーや型変換の警告を確認してください）",
+					entry.Table, loaded, entry.Rows)
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,118 @@
+// internal/snapshot/snapshot.go
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-db-performance-study/internal/testdata"
+)
+
+// FormatVersion スナップショットの形式のバージョン（互換性のない変更をしたら上げる）
+const FormatVersion = 1
+
+// ManifestFile マニフェストのファイル名
+const ManifestFile = "manifest.json"
+
+// fileFormat テーブルファイルの形式（LOAD DATA 用 CSV を gzip 圧縮したもの）
+const fileFormat = "csv+gzip"
+
+// table スナップショット対象のテーブル
+type table struct {
+	Name    string
+	OrderBy string
+}
+
+// tables スナップショット対象のテーブル（読み込み順。親テーブルが先）
+var tables = []table{
+	{Name: "users", OrderBy: "id"},
+	{Name: "tags", OrderBy: "id"},
+	{Name: "posts", OrderBy: "id"},
+	{Name: "post_tags", OrderBy: "post_id, tag_id"},
+	{Name: "comments", OrderBy: "id"},
+}
+
+// Manifest スナップショットの内容の一覧
+type Manifest struct {
+	Version   int          `json:"version"`
+	Format    string       `json:"format"`
+	CreatedAt time.Time    `json:"created_at"`
+	Database  string       `json:"database"` // エクスポート元のデータベース名
+	Tables    []TableEntry `json:"tables"`
+
+	// Dataset testdata.ComputeChecksum の結果（インポート後の照合に使う）
+	Dataset *testdata.DatasetChecksum `json:"dataset"`
+	// Generator 最後に完了したデータ生成の設定（記録がなければ nil）
+	Generator *GeneratorInfo `json:"generator,omitempty"`
+}
+
+// TableEntry 1テーブル分のファイル情報
+type TableEntry struct {
+	Table   string   `json:"table"`
+	File    string   `json:"file"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows"`
+	SHA256  string   `json:"sha256"` // 展開後の CSV の SHA-256
+	Bytes   int64    `json:"bytes"`  // 圧縮後のファイルサイズ
+}
+
+// GeneratorInfo データを生成したときの設定
+type GeneratorInfo struct {
+	RunID    uint            `json:"run_id"`
+	Scenario string          `json:"scenario,omitempty"`
+	Seed     int64           `json:"seed"`
+	BaseTime time.Time       `json:"base_time"`
+	Config   json.RawMessage `json:"config"`
+}
+
+// TotalRows 全テーブルの行数の合計
+func (m *Manifest) TotalRows() int64 {
+	var total int64
+	for _, t := range m.Tables {
+		total += t.Rows
+	}
+	return total
+}
+
+// ReadManifest スナップショットのマニフェストを読み込み、形式を検証
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s に %s がありません（エクスポートが完了していない可能性があります）", dir, ManifestFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("マニフェスト読み込みエラー: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("マニフェスト解析エラー: %w", err)
+	}
+	if m.Version < 1 || m.Version > FormatVersion {
+		return nil, fmt.Errorf("未対応のスナップショット形式です: version %d（対応: 1-%d）", m.Version, FormatVersion)
+	}
+	if m.Format != fileFormat {
+		return nil, fmt.Errorf("未対応のファイル形式です: %s", m.Format)
+	}
+	return &m, nil
+}
+
+// writeManifest マニフェストを書き込む（一時ファイルに書いてから置き換える）
+func writeManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("マニフェスト変換エラー: %w", err)
+	}
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("マニフェスト書き込みエラー: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, ManifestFile)); err != nil {
+		return fmt.Errorf("マニフェスト書き込みエラー: %w", err)
+	}
+	return nil
+}
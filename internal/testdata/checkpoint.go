@@ -0,0 +1,254 @@
+// internal/testdata/checkpoint.go
+package testdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 実行の状態
+const (
+	RunStatusRunning   = "running"
+	RunStatusCompleted = "completed"
+)
+
+// GenerationRun データ生成の1回の実行（再開に必要な設定とシードを保持）
+type GenerationRun struct {
+	ID       uint      `gorm:"primarykey" json:"id"`
+	Scenario string    `gorm:"size:100;not null;default:'';index" json:"scenario"` // 生成したシナリオ名（空の場合は不明）
+	Seed     int64     `gorm:"not null" json:"seed"`
+	BaseTime time.Time `gorm:"not null" json:"base_time"`
+	Config   string    `gorm:"type:text;not null" json:"-"`        // GeneratorConfig の JSON
+	Safe     bool      `gorm:"not null;default:false" json:"safe"` // GenerateAllSafe で生成したか
+	Status   string    `gorm:"size:20;not null;index" json:"status"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GenerationPhase フェーズごとの開始ID（再開時も同じIDを割り当てるために記録）
+type GenerationPhase struct {
+	RunID   uint   `gorm:"primaryKey;autoIncrement:false" json:"run_id"`
+	Phase   string `gorm:"primaryKey;size:32" json:"phase"`
+	FirstID uint   `gorm:"not null" json:"first_id"`
+	Total   int    `gorm:"not null" json:"total"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GenerationBatch 挿入が確定したバッチ
+//
+// バッチの挿入と同じトランザクションで記録するため、
+// ここに記録されたバッチは必ずコミット済みで、記録のないバッチは1行も残っていない。
+type GenerationBatch struct {
+	RunID      uint   `gorm:"primaryKey;autoIncrement:false" json:"run_id"`
+	Phase      string `gorm:"primaryKey;size:32" json:"phase"`
+	BatchIndex int    `gorm:"primaryKey;autoIncrement:false" json:"batch_index"`
+	Rows       int    `gorm:"not null" json:"rows"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CheckpointTables チェックポイント用のテーブル名（クリーンアップ用）
+var CheckpointTables = []string{"generation_batches", "generation_phases", "generation_runs"}
+
+// migrateCheckpoints チェックポイント用のテーブルを作成
+func migrateCheckpoints(db *gorm.DB) error {
+	if err := db.AutoMigrate(&GenerationRun{}, &GenerationPhase{}, &GenerationBatch{}); err != nil {
+		return fmt.Errorf("チェックポイントテーブル作成エラー: %w", err)
+	}
+	return nil
+}
+
+// LatestUnfinishedRun 完了していない最新の実行を取得（なければ nil）
+func LatestUnfinishedRun(db *gorm.DB) (*GenerationRun, error) {
+	if err := migrateCheckpoints(db); err != nil {
+		return nil, err
+	}
+
+	var run GenerationRun
+	err := db.Where("status = ?", RunStatusRunning).Order("id DESC").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("実行履歴取得エラー: %w", err)
+	}
+	return &run, nil
+}
+
+// ResumeOptions 再開時に変更できる設定（生成されるデータには影響しないもの）
+type ResumeOptions struct {
+	Workers        int
+	InsertMethod   InsertMethod
+	DisableIndexes bool
+	DisableChecks  bool
+}
+
+// ResumeDataGenerator 中断された最新の実行を、保存済みの設定とシードで再開する生成器を作成
+func ResumeDataGenerator(db *gorm.DB, opts ResumeOptions) (*DataGenerator, error) {
+	run, err := LatestUnfinishedRun(db)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, fmt.Errorf("再開できる実行がありません")
+	}
+
+	var config GeneratorConfig
+	if err := json.Unmarshal([]byte(run.Config), &config); err != nil {
+		return nil, fmt.Errorf("実行 #%d の設定の復元エラー: %w", run.ID, err)
+	}
+	config.Seed = run.Seed
+	config.BaseTime = run.BaseTime
+	config.Workers = opts.Workers
+	config.InsertMethod = opts.InsertMethod
+	config.DisableIndexes = opts.DisableIndexes
+	config.DisableChecks = opts.DisableChecks
+
+	g := NewDataGenerator(db, config)
+	g.run = run
+	return g, nil
+}
+
+// Resume 再開した実行を、元と同じ生成方法で最後まで進める
+func (g *DataGenerator) Resume() error {
+	if g.run == nil {
+		return fmt.Errorf("再開する実行が設定されていません（ResumeDataGenerator で作成してください）")
+	}
+
+	var done []GenerationBatch
+	if err := g.db.Where("run_id = ?", g.run.ID).Find(&done).Error; err != nil {
+		return fmt.Errorf("完了済みバッチ取得エラー: %w", err)
+	}
+	rows := map[string]int{}
+	for _, b := range done {
+		rows[b.Phase] += b.Rows
+	}
+	log.Printf("実行 #%d を再開します（開始: %s）", g.run.ID, g.run.CreatedAt.Format(time.RFC3339))
+	for _, phase := range []string{"users", "tags", "posts", "comments", "post_tags"} {
+		if rows[phase] > 0 {
+			log.Printf("  %-10s %d件 挿入済み", phase, rows[phase])
+		}
+	}
+
+	if g.run.Safe {
+		return g.GenerateAllSafe()
+	}
+	return g.GenerateAll()
+}
+
+// startRun 実行記録を作成（再開時は既存の記録を使う）
+func (g *DataGenerator) startRun(safe bool) error {
+	if g.run != nil {
+		return nil
+	}
+	if err := migrateCheckpoints(g.db); err != nil {
+		return err
+	}
+
+	config := g.config
+	config.BaseTime = g.baseTime
+	// 書き込み方法などは生成結果に影響せず、再開時に指定し直せるため保存しない
+	config.Workers = 0
+	config.InsertMethod = ""
+	config.DisableIndexes = false
+	config.DisableChecks = false
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("設定の保存エラー: %w", err)
+	}
+
+	run := &GenerationRun{
+		Scenario: g.config.Scenario,
+		Seed:     g.config.Seed,
+		BaseTime: g.baseTime,
+		Config:   string(data),
+		Safe:     safe,
+		Status:   RunStatusRunning,
+	}
+	if err := g.db.WithContext(g.ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("実行記録作成エラー: %w", err)
+	}
+
+	g.run = run
+	log.Printf("実行 #%d を開始しました（中断した場合は -resume で再開できます）", run.ID)
+	return nil
+}
+
+// finishRun 実行を完了にする
+func (g *DataGenerator) finishRun() error {
+	if g.run == nil {
+		return nil
+	}
+	err := g.db.WithContext(g.ctx).Model(g.run).Update("status", RunStatusCompleted).Error
+	if err != nil {
+		return fmt.Errorf("実行記録更新エラー: %w", err)
+	}
+	return nil
+}
+
+// beginPhase フェーズの開始IDを決める
+//
+// 初回は既存の最大ID + 1 を記録し、再開時は記録済みの値を使う。
+// これにより再開後のバッチにも中断前と同じIDが割り当てられる。
+func (g *DataGenerator) beginPhase(table string, total int) (uint, error) {
+	if g.run == nil {
+		return g.nextID(table)
+	}
+
+	var phase GenerationPhase
+	err := g.db.WithContext(g.ctx).Where("run_id = ? AND phase = ?", g.run.ID, table).First(&phase).Error
+	if err == nil {
+		if phase.Total != total {
+			return 0, fmt.Errorf("%s の件数が中断前と異なります（記録: %d, 今回: %d）", table, phase.Total, total)
+		}
+		return phase.FirstID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("%s のチェックポイント取得エラー: %w", table, err)
+	}
+
+	firstID, err := g.nextID(table)
+	if err != nil {
+		return 0, err
+	}
+	phase = GenerationPhase{RunID: g.run.ID, Phase: table, FirstID: firstID, Total: total}
+	if err := g.db.WithContext(g.ctx).Create(&phase).Error; err != nil {
+		return 0, fmt.Errorf("%s のチェックポイント作成エラー: %w", table, err)
+	}
+	return firstID, nil
+}
+
+// completedBatches 記録済みのバッチ番号と行数を取得
+func (g *DataGenerator) completedBatches(phase string) (map[int]int, error) {
+	done := map[int]int{}
+	if g.run == nil {
+		return done, nil
+	}
+
+	var batches []GenerationBatch
+	err := g.db.WithContext(g.ctx).
+		Where("run_id = ? AND phase = ?", g.run.ID, phase).
+		Find(&batches).Error
+	if err != nil {
+		return nil, fmt.Errorf("%s の完了済みバッチ取得エラー: %w", phase, err)
+	}
+	for _, b := range batches {
+		done[b.BatchIndex] = b.Rows
+	}
+	return done, nil
+}
+
+// recordBatch バッチの完了を記録（挿入と同じトランザクション内で呼ぶ）
+func (g *DataGenerator) recordBatch(tx *gorm.DB, phase string, index, rows int) error {
+	if g.run == nil {
+		return nil
+	}
+	return tx.Create(&GenerationBatch{RunID: g.run.ID, Phase: phase, BatchIndex: index, Rows: rows}).Error
+}
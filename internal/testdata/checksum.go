@@ -0,0 +1,148 @@
+// internal/testdata/checksum.go
+package testdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// checksumTable チェックサム対象のテーブル定義
+type checksumTable struct {
+	Name    string
+	Columns []string
+	OrderBy string
+}
+
+// checksumTables チェックサム対象のテーブルと列
+//
+// password はソルト付きハッシュのため、updated_at は生成後の更新で変わるため除外する。
+var checksumTables = []checksumTable{
+	{
+		Name:    "users",
+		Columns: []string{"id", "name", "email", "email_verified_at", "remember_token", "created_at"},
+		OrderBy: "id",
+	},
+	{
+		Name:    "tags",
+		Columns: []string{"id", "name", "slug", "color", "description", "post_count", "is_active", "created_at"},
+		OrderBy: "id",
+	},
+	{
+		Name:    "posts",
+		Columns: []string{"id", "user_id", "title", "slug", "body", "excerpt", "status", "view_count", "created_at"},
+		OrderBy: "id",
+	},
+	{
+		Name:    "post_tags",
+		Columns: []string{"post_id", "tag_id"},
+		OrderBy: "post_id, tag_id",
+	},
+	{
+		Name:    "comments",
+		Columns: []string{"id", "post_id", "user_id", "parent_id", "body", "status", "ip_address", "user_agent", "is_edited", "created_at"},
+		OrderBy: "id",
+	},
+}
+
+// TableChecksum テーブル単位のチェックサム
+type TableChecksum struct {
+	Table    string `json:"table"`
+	Rows     int64  `json:"rows"`
+	Checksum string `json:"checksum"`
+}
+
+// DatasetChecksum データセット全体のチェックサム
+type DatasetChecksum struct {
+	Tables   []TableChecksum `json:"tables"`
+	Checksum string          `json:"checksum"`
+}
+
+// ComputeChecksum 全テーブルの内容から決定的なチェックサムを計算
+func ComputeChecksum(db *gorm.DB) (*DatasetChecksum, error) {
+	result := &DatasetChecksum{}
+	total := sha256.New()
+
+	for _, table := range checksumTables {
+		tc, err := checksumOf(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("テーブル %s のチェックサム計算エラー: %w", table.Name, err)
+		}
+		result.Tables = append(result.Tables, *tc)
+		fmt.Fprintf(total, "%s:%d:%s\n", tc.Table, tc.Rows, tc.Checksum)
+	}
+
+	result.Checksum = hex.EncodeToString(total.Sum(nil))
+	return result, nil
+}
+
+// Equal 2つのチェックサムが一致するか判定
+func (c *DatasetChecksum) Equal(other *DatasetChecksum) bool {
+	return c.Checksum == other.Checksum
+}
+
+// checksumOf 1テーブル分のチェックサムを主キー順にストリーミングで計算
+func checksumOf(db *gorm.DB, table checksumTable) (*TableChecksum, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s",
+		strings.Join(table.Columns, ", "), table.Name, table.OrderBy)
+
+	rows, err := db.Raw(query).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	h := sha256.New()
+	values := make([]interface{}, len(table.Columns))
+	pointers := make([]interface{}, len(table.Columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		writeRow(h, values)
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &TableChecksum{
+		Table:    table.Name,
+		Rows:     count,
+		Checksum: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// writeRow 1行分の値を区切り文字付きでハッシュに書き込む
+func writeRow(h hash.Hash, values []interface{}) {
+	for i, v := range values {
+		if i > 0 {
+			h.Write([]byte{0x1f})
+		}
+		switch val := v.(type) {
+		case nil:
+			h.Write([]byte(`\N`))
+		case []byte:
+			h.Write(val)
+		case time.Time:
+			// タイムゾーン設定の違いに影響されないよう UTC に揃える
+			h.Write([]byte(val.UTC().Format(time.RFC3339Nano)))
+		case int64:
+			h.Write([]byte(strconv.FormatInt(val, 10)))
+		default:
+			fmt.Fprintf(h, "%v", val)
+		}
+	}
+	h.Write([]byte{0x1e})
+}
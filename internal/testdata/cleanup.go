@@ -0,0 +1,286 @@
+// internal/testdata/cleanup.go
+package testdata
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"go-db-performance-study/internal/database"
+
+	"gorm.io/gorm"
+)
+
+// CleanupMode 生成データの削除方法
+type CleanupMode string
+
+const (
+	// CleanupDelete 全テーブルを DELETE で削除（行数に比例して遅いが、権限やロックの影響が少ない）
+	CleanupDelete CleanupMode = "delete"
+	// CleanupTruncate 外部キー検査を止めて TRUNCATE（行数に関係なく速い）
+	CleanupTruncate CleanupMode = "truncate"
+	// CleanupRecreate テーブルを削除してマイグレーションし直す（スキーマも初期状態に戻す）
+	CleanupRecreate CleanupMode = "recreate"
+	// CleanupChunked 指定したシナリオの実行で生成した行だけを、主キー範囲ごとに少しずつ削除
+	CleanupChunked CleanupMode = "chunked"
+)
+
+// Validate 削除方法の検証
+func (m CleanupMode) Validate() error {
+	switch m {
+	case CleanupDelete, CleanupTruncate, CleanupRecreate, CleanupChunked:
+		return nil
+	}
+	return fmt.Errorf("未知の削除方法: %s (delete/truncate/recreate/chunked)", m)
+}
+
+// DefaultCleanupChunkSize chunked で1文あたりに削除するIDの範囲
+const DefaultCleanupChunkSize = 10000
+
+// CleanupOptions 削除の設定
+type CleanupOptions struct {
+	Mode CleanupMode
+	// Scenario chunked で削除する実行のシナリオ名
+	Scenario string
+	// ChunkSize chunked で1文あたりに削除するIDの範囲（0 の場合は DefaultCleanupChunkSize）
+	ChunkSize int
+}
+
+// CleanupStat テーブルごとの削除結果
+type CleanupStat struct {
+	Table   string
+	Rows    int64 // 削除した行数（TRUNCATE・DROP では数えないため -1）
+	Elapsed time.Duration
+}
+
+// dataTables 生成データのテーブル（子テーブルから順に並べる）
+var dataTables = []string{"comments", "post_tags", "posts", "tags", "users"}
+
+// allTables 全削除で空にするテーブル（生成データ・slug 履歴・監査ログ・日別集計・チェックポイント）
+//
+// slug 履歴・モデレーションの監査ログ・コメントの日別集計は生成時には作られないが、
+// 投稿・タグ・コメントを全て消すと指す先や集計元がなくなるため一緒に消す。
+func allTables() []string {
+	tables := append([]string{"comment_daily_rollups", "moderation_actions", "slug_histories"}, dataTables...)
+	return append(tables, CheckpointTables...)
+}
+
+// Cleanup 生成データとチェックポイントを削除
+//
+// chunked 以外は全データを削除する。chunked はチェックポイントに記録された
+// 各フェーズのID範囲を使い、指定したシナリオの実行で生成した行だけを削除する。
+func Cleanup(db *gorm.DB, opts CleanupOptions) ([]CleanupStat, error) {
+	if err := opts.Mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch opts.Mode {
+	case CleanupTruncate:
+		return truncateTables(db)
+	case CleanupRecreate:
+		return recreateTables(db)
+	case CleanupChunked:
+		return cleanupScenario(db, opts)
+	default:
+		return deleteTables(db)
+	}
+}
+
+// deleteTables 全テーブルを DELETE で削除し、AUTO_INCREMENT を戻す
+func deleteTables(db *gorm.DB) ([]CleanupStat, error) {
+	var stats []CleanupStat
+	for _, table := range allTables() {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+		start := time.Now()
+		result := db.Exec(fmt.Sprintf("DELETE FROM %s", table))
+		if result.Error != nil {
+			return nil, fmt.Errorf("テーブル %s の削除エラー: %w", table, result.Error)
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = 1", table)).Error; err != nil {
+			log.Printf("AUTO_INCREMENT リセット警告 (%s): %v", table, err)
+		}
+		stats = append(stats, CleanupStat{Table: table, Rows: result.RowsAffected, Elapsed: time.Since(start)})
+	}
+	return stats, nil
+}
+
+// truncateTables 外部キー検査を止めて全テーブルを TRUNCATE
+//
+// 参照されているテーブルは外部キー検査が有効だと TRUNCATE できないため、
+// 同じ接続で foreign_key_checks を切り替える。AUTO_INCREMENT も初期値に戻る。
+func truncateTables(db *gorm.DB) ([]CleanupStat, error) {
+	var stats []CleanupStat
+	err := db.Connection(func(conn *gorm.DB) (err error) {
+		if err := conn.Exec("SET SESSION foreign_key_checks = 0").Error; err != nil {
+			return fmt.Errorf("外部キー検査の無効化エラー: %w", err)
+		}
+		defer func() {
+			if restoreErr := conn.Exec("SET SESSION foreign_key_checks = 1").Error; restoreErr != nil && err == nil {
+				err = fmt.Errorf("外部キー検査の再有効化エラー: %w", restoreErr)
+			}
+		}()
+
+		for _, table := range allTables() {
+			if !conn.Migrator().HasTable(table) {
+				continue
+			}
+			start := time.Now()
+			if err := conn.Exec(fmt.Sprintf("TRUNCATE TABLE `%s`", table)).Error; err != nil {
+				return fmt.Errorf("テーブル %s の TRUNCATE エラー: %w", table, err)
+			}
+			stats = append(stats, CleanupStat{Table: table, Rows: -1, Elapsed: time.Since(start)})
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// recreateTables 全テーブルを削除してマイグレーションし直す
+//
+// チェックポイントのテーブルは次回の生成時に作成される。
+func recreateTables(db *gorm.DB) ([]CleanupStat, error) {
+	start := time.Now()
+	if err := database.DropAllTables(db); err != nil {
+		return nil, err
+	}
+	for _, table := range CheckpointTables {
+		if err := db.Migrator().DropTable(table); err != nil {
+			return nil, fmt.Errorf("テーブル %s の削除エラー: %w", table, err)
+		}
+	}
+	dropped := time.Since(start)
+
+	start = time.Now()
+	if err := database.Migrate(db); err != nil {
+		return nil, err
+	}
+	return []CleanupStat{
+		{Table: "(DROP TABLE)", Rows: -1, Elapsed: dropped},
+		{Table: "(マイグレーション)", Rows: -1, Elapsed: time.Since(start)},
+	}, nil
+}
+
+// cleanupScenario シナリオの実行で生成した行を、ID範囲ごとの短いトランザクションで削除
+//
+// 1文で大量の行を削除するとロックと undo ログが膨らむため、ChunkSize ずつに分ける。
+// 生成器は実行ごとに専用のID範囲を割り当て、参照先も同じ実行の行に限られるため、
+// 範囲で削除しても他の実行のデータは残る。
+func cleanupScenario(db *gorm.DB, opts CleanupOptions) ([]CleanupStat, error) {
+	if opts.Scenario == "" {
+		return nil, fmt.Errorf("chunked で削除するシナリオ名を指定してください")
+	}
+	chunk := opts.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultCleanupChunkSize
+	}
+	if err := migrateCheckpoints(db); err != nil {
+		return nil, err
+	}
+
+	var runs []GenerationRun
+	if err := db.Where("scenario = ?", opts.Scenario).Order("id").Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("実行履歴取得エラー: %w", err)
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("シナリオ %s の実行記録がありません", opts.Scenario)
+	}
+
+	totals := map[string]*CleanupStat{}
+	for _, table := range dataTables {
+		totals[table] = &CleanupStat{Table: table}
+	}
+	for _, run := range runs {
+		var phases []GenerationPhase
+		if err := db.Where("run_id = ?", run.ID).Find(&phases).Error; err != nil {
+			return nil, fmt.Errorf("実行 #%d のフェーズ取得エラー: %w", run.ID, err)
+		}
+		ranges := map[string]GenerationPhase{}
+		for _, p := range phases {
+			ranges[p.Phase] = p
+		}
+
+		log.Printf("実行 #%d (%s, %s) を削除中...", run.ID, run.Scenario, run.CreatedAt.Format(time.RFC3339))
+		// 子テーブルから順に削除。返信は親より大きいIDを持つため、コメントは大きいIDから消す
+		steps := []struct {
+			table, column, phase string
+			desc                 bool
+		}{
+			{"comments", "id", "comments", true},
+			{"post_tags", "post_id", "posts", false},
+			{"post_tags", "tag_id", "tags", false},
+			{"posts", "id", "posts", false},
+			{"tags", "id", "tags", false},
+			{"users", "id", "users", false},
+		}
+		for _, s := range steps {
+			p, ok := ranges[s.phase]
+			if !ok || p.Total == 0 {
+				continue
+			}
+			start := time.Now()
+			rows, err := deleteRange(db, s.table, s.column, p.FirstID, p.FirstID+uint(p.Total)-1, chunk, s.desc)
+			if err != nil {
+				return nil, fmt.Errorf("実行 #%d の %s 削除エラー: %w", run.ID, s.table, err)
+			}
+			totals[s.table].Rows += rows
+			totals[s.table].Elapsed += time.Since(start)
+		}
+
+		if err := deleteRun(db, run.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := make([]CleanupStat, 0, len(dataTables))
+	for _, table := range dataTables {
+		stats = append(stats, *totals[table])
+	}
+	return stats, nil
+}
+
+// deleteRange 列の値が first〜last の行を chunk 件の範囲ずつ削除し、削除した行数を返す
+//
+// desc の場合は大きい値の範囲から順に、範囲内も ORDER BY id DESC で削除する
+// （同じテーブル内の参照で、参照元の行を先に消すため）。
+func deleteRange(db *gorm.DB, table, column string, first, last uint, chunk int, desc bool) (int64, error) {
+	var total int64
+	size := uint(chunk)
+	for n := uint(0); n <= last-first; n += size {
+		lo, hi := first+n, last
+		if last-lo >= size {
+			hi = lo + size - 1
+		}
+		order := ""
+		if desc {
+			hi, lo = last-n, first
+			if hi-first >= size {
+				lo = hi - size + 1
+			}
+			order = " ORDER BY id DESC"
+		}
+		result := db.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `%s` BETWEEN ? AND ?%s", table, column, order), lo, hi)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+	}
+	return total, nil
+}
+
+// deleteRun 実行のチェックポイントを削除
+func deleteRun(db *gorm.DB, runID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("run_id = ?", runID).Delete(&GenerationBatch{}).Error; err != nil {
+			return fmt.Errorf("実行 #%d のバッチ記録削除エラー: %w", runID, err)
+		}
+		if err := tx.Where("run_id = ?", runID).Delete(&GenerationPhase{}).Error; err != nil {
+			return fmt.Errorf("実行 #%d のフェーズ記録削除エラー: %w", runID, err)
+		}
+		if err := tx.Delete(&GenerationRun{}, runID).Error; err != nil {
+			return fmt.Errorf("実行 #%d の記録削除エラー: %w", runID, err)
+		}
+		return nil
+	})
+}
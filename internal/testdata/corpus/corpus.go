@@ -0,0 +1,317 @@
+// internal/testdata/corpus/corpus.go
+package corpus
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-db-performance-study/internal/testdata/distribution"
+	"go-db-performance-study/internal/textutil"
+)
+
+// Mode 文章の生成方式
+type Mode string
+
+const (
+	// ModeTemplate 生成器に組み込みの定型文（コーパスを使わない。既存のデータセットを再現する）
+	ModeTemplate Mode = "template"
+	// ModeWords 単語リストを文型に当てはめて組み立てる
+	ModeWords Mode = "words"
+	// ModeMarkov 埋め込みの例文から学習したマルコフ連鎖で生成
+	ModeMarkov Mode = "markov"
+)
+
+// UsesCorpus コーパスから文章を生成する方式か
+func (m Mode) UsesCorpus() bool {
+	return m == ModeWords || m == ModeMarkov
+}
+
+// 生成する文章の上限
+const (
+	// MaxBodyBytes 投稿本文の上限（TEXT 型の 65535 バイトに余裕を持たせる）
+	MaxBodyBytes = 60000
+	// MaxCommentRunes コメント本文の上限（HTML エスケープ後も検証の 2000 文字に収まるようにする）
+	MaxCommentRunes = 1000
+	// maxTitleRunes タイトルの上限（posts.title は 255 文字）
+	maxTitleRunes = 120
+	// maxSentenceTokens マルコフ連鎖で1文に使う最大トークン数
+	maxSentenceTokens = 80
+)
+
+// Length 文数などの長さの分布
+//
+// [Min, Max] の範囲から分布に従って選ぶ。指数分布や Zipf にすると
+// 「短い文章が多く、ごく一部が長い」実際の投稿に近い長さになる。
+type Length struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+
+	distribution.Distribution `yaml:",inline"`
+}
+
+// Validate 長さの設定の検証
+func (l Length) Validate() error {
+	if l.Min < 0 || l.Max < 0 {
+		return fmt.Errorf("長さに負の値は指定できません: %d-%d", l.Min, l.Max)
+	}
+	if l.Max < l.Min {
+		return fmt.Errorf("max は min 以上にしてください: %d-%d", l.Min, l.Max)
+	}
+	return l.Distribution.Validate()
+}
+
+// String ログ出力用の表現
+func (l Length) String() string {
+	return fmt.Sprintf("%d-%d %s", l.Min, l.Max, l.Distribution)
+}
+
+// withDefaults 未指定（min・max とも 0）なら既定値を返す
+func (l Length) withDefaults(d Length) Length {
+	if l.Min == 0 && l.Max == 0 {
+		return d
+	}
+	if l.Min == 0 {
+		l.Min = 1
+	}
+	return l
+}
+
+// sample 長さを1つ選ぶ
+func (l Length) sample(r *rand.Rand) int {
+	return distribution.NewSampler(l.Distribution, r).Between(l.Min, l.Max)
+}
+
+// 長さの既定値
+var (
+	DefaultPostSentences      = Length{Min: 3, Max: 120, Distribution: distribution.Distribution{Kind: distribution.Exponential, Rate: 4}}
+	DefaultParagraphSentences = Length{Min: 1, Max: 6}
+	DefaultCommentSentences   = Length{Min: 1, Max: 12, Distribution: distribution.Distribution{Kind: distribution.Exponential, Rate: 3}}
+	DefaultReplySentences     = Length{Min: 1, Max: 4, Distribution: distribution.Distribution{Kind: distribution.Exponential, Rate: 3}}
+)
+
+// Config 文章生成の設定
+//
+// yaml タグはシナリオ定義ファイルの text セクションの項目名。
+type Config struct {
+	// Mode 生成方式（空の場合は template）
+	Mode Mode `yaml:"mode"`
+	// Emoji 文末・タイトルに絵文字（4バイトの UTF-8 や ZWJ 連結を含む）を付ける確率
+	Emoji float64 `yaml:"emoji"`
+
+	// PostSentences 投稿本文の文数
+	PostSentences Length `yaml:"post_sentences"`
+	// ParagraphSentences 本文の1段落あたりの文数
+	ParagraphSentences Length `yaml:"paragraph_sentences"`
+	// CommentSentences ルートコメントの文数
+	CommentSentences Length `yaml:"comment_sentences"`
+	// ReplySentences 返信の文数
+	ReplySentences Length `yaml:"reply_sentences"`
+}
+
+// Validate 設定値の検証
+func (c Config) Validate() error {
+	switch c.Mode {
+	case "", ModeTemplate, ModeWords, ModeMarkov:
+	default:
+		return fmt.Errorf("未知の文章生成方式: %s (template/words/markov)", c.Mode)
+	}
+	if c.Emoji < 0 || c.Emoji > 1 {
+		return fmt.Errorf("絵文字の確率は 0.0-1.0 の範囲で指定してください: %v", c.Emoji)
+	}
+	lengths := []struct {
+		name   string
+		length Length
+	}{
+		{"post_sentences", c.PostSentences},
+		{"paragraph_sentences", c.ParagraphSentences},
+		{"comment_sentences", c.CommentSentences},
+		{"reply_sentences", c.ReplySentences},
+	}
+	for _, l := range lengths {
+		if err := l.length.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", l.name, err)
+		}
+	}
+	return nil
+}
+
+// WithDefaults 未指定の項目に既定値を設定
+func (c Config) WithDefaults() Config {
+	if c.Mode == "" {
+		c.Mode = ModeTemplate
+	}
+	c.PostSentences = c.PostSentences.withDefaults(DefaultPostSentences)
+	c.ParagraphSentences = c.ParagraphSentences.withDefaults(DefaultParagraphSentences)
+	c.CommentSentences = c.CommentSentences.withDefaults(DefaultCommentSentences)
+	c.ReplySentences = c.ReplySentences.withDefaults(DefaultReplySentences)
+	return c
+}
+
+// Generator コーパスから文章を生成する
+//
+// 単語リストやマルコフ連鎖は読み取り専用なので、複数のワーカーで共有できる。
+// 乱数源は呼び出し側が渡すため、同じ乱数列からは同じ文章になる。
+type Generator struct {
+	config Config
+	lang   *language
+	chain  *chain // ModeMarkov の場合のみ
+}
+
+// New 言語（ja/en）と設定から生成器を作成
+func New(lang string, config Config) (*Generator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config = config.WithDefaults()
+	if !config.Mode.UsesCorpus() {
+		return nil, fmt.Errorf("%s はコーパスを使わない生成方式です", config.Mode)
+	}
+
+	l, err := loadLanguage(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Generator{config: config, lang: l}
+	if config.Mode == ModeMarkov {
+		g.chain = l.chain()
+	}
+	return g, nil
+}
+
+// Config 既定値を補った設定
+func (g *Generator) Config() Config {
+	return g.config
+}
+
+// Title 投稿のタイトルを生成
+func (g *Generator) Title(r *rand.Rand) string {
+	title := g.fill(r, g.lang.titles[r.Intn(len(g.lang.titles))])
+	if g.lang.capitalize {
+		first, size := utf8.DecodeRuneInString(title)
+		title = string(unicode.ToUpper(first)) + title[size:]
+	}
+	title = g.withEmoji(r, title)
+	return textutil.Truncate(title, maxTitleRunes, "")
+}
+
+// Body 投稿の本文を生成（段落は空行で区切る）
+func (g *Generator) Body(r *rand.Rand) string {
+	total := g.config.PostSentences.sample(r)
+
+	var b strings.Builder
+	for written := 0; written < total; {
+		n := g.config.ParagraphSentences.sample(r)
+		if n > total-written {
+			n = total - written
+		}
+		paragraph := g.paragraph(r, n)
+		if b.Len()+len(paragraph)+2 > MaxBodyBytes {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(paragraph)
+		written += n
+	}
+	if b.Len() == 0 {
+		return g.sentence(r)
+	}
+	return b.String()
+}
+
+// Comment ルートコメントの本文を生成
+func (g *Generator) Comment(r *rand.Rand) string {
+	return g.comment(r, g.config.CommentSentences)
+}
+
+// Reply 返信の本文を生成
+func (g *Generator) Reply(r *rand.Rand) string {
+	return g.comment(r, g.config.ReplySentences)
+}
+
+// comment 上限の文字数に収まる範囲で文を並べる（少なくとも1文は含める）
+func (g *Generator) comment(r *rand.Rand, length Length) string {
+	n := length.sample(r)
+	body := g.sentence(r)
+	runes := utf8.RuneCountInString(body)
+	for i := 1; i < n; i++ {
+		s := g.sentence(r)
+		size := utf8.RuneCountInString(s) + len(g.lang.sentenceSep)
+		if runes+size > MaxCommentRunes {
+			break
+		}
+		body += g.lang.sentenceSep + s
+		runes += size
+	}
+	return textutil.Truncate(body, MaxCommentRunes, "")
+}
+
+// paragraph n 文からなる段落
+func (g *Generator) paragraph(r *rand.Rand, n int) string {
+	sentences := make([]string, n)
+	for i := range sentences {
+		sentences[i] = g.sentence(r)
+	}
+	return strings.Join(sentences, g.lang.sentenceSep)
+}
+
+// sentence 1文を生成（確率 Emoji で文末に絵文字を付ける）
+func (g *Generator) sentence(r *rand.Rand) string {
+	var s string
+	if g.chain != nil {
+		s = g.lang.join(g.chain.generate(r, maxSentenceTokens))
+	} else {
+		s = g.fill(r, g.lang.patterns[r.Intn(len(g.lang.patterns))])
+	}
+	return g.withEmoji(r, s)
+}
+
+// withEmoji 確率 Emoji で末尾に絵文字を付ける
+func (g *Generator) withEmoji(r *rand.Rand, s string) string {
+	if g.config.Emoji == 0 || r.Float64() >= g.config.Emoji {
+		return s
+	}
+	return s + g.lang.emojiSep + emoji[r.Intn(len(emoji))]
+}
+
+// fill 文型の {noun} {verb} {adj} {num} を単語リストから選んだ語で置き換える
+func (g *Generator) fill(r *rand.Rand, pattern string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(pattern, '{')
+		if start < 0 {
+			b.WriteString(pattern)
+			return b.String()
+		}
+		end := strings.IndexByte(pattern[start:], '}')
+		if end < 0 {
+			b.WriteString(pattern)
+			return b.String()
+		}
+		b.WriteString(pattern[:start])
+		b.WriteString(g.word(r, pattern[start+1:start+end]))
+		pattern = pattern[start+end+1:]
+	}
+}
+
+// word 文型の差し込み位置の種類に応じた語
+func (g *Generator) word(r *rand.Rand, slot string) string {
+	switch slot {
+	case "noun":
+		return g.lang.nouns[r.Intn(len(g.lang.nouns))]
+	case "verb":
+		return g.lang.verbs[r.Intn(len(g.lang.verbs))]
+	case "adj":
+		return g.lang.adjectives[r.Intn(len(g.lang.adjectives))]
+	case "num":
+		// 小さい数ほど出やすくする（2〜10000）
+		return strconv.Itoa(2 + int(r.ExpFloat64()*50)%9999)
+	}
+	return "{" + slot + "}"
+}
@@ -0,0 +1,167 @@
+// internal/testdata/corpus/language.go
+package corpus
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// data 言語ごとの単語リスト・文型・例文（data/<言語>/*.txt）と絵文字の一覧
+//
+// 各ファイルは1行1項目。空行と # で始まる行は無視する。
+//
+//go:embed data
+var data embed.FS
+
+// emoji 文末などに付ける絵文字（data/emoji.txt）
+var emoji []string
+
+// language 1つの言語の語彙
+type language struct {
+	nouns      []string
+	verbs      []string
+	adjectives []string
+	patterns   []string   // 本文の文型
+	titles     []string   // タイトルの文型
+	examples   [][]string // マルコフ連鎖の学習用の文（トークン列）
+
+	format
+
+	chainOnce sync.Once
+	markov    *chain
+}
+
+// format 言語ごとの書き方
+type format struct {
+	tokenSep    string // トークンの区切り（日本語は詰めて書く）
+	sentenceSep string // 文の区切り
+	emojiSep    string // 文と絵文字の区切り
+	capitalize  bool   // タイトルの先頭を大文字にする
+}
+
+// formats 対応する言語と書き方
+var formats = map[string]format{
+	"ja": {tokenSep: "", sentenceSep: "", emojiSep: ""},
+	"en": {tokenSep: " ", sentenceSep: " ", emojiSep: " ", capitalize: true},
+}
+
+var (
+	loadOnce  sync.Once
+	languages map[string]*language
+	loadErr   error
+)
+
+// loadLanguage 埋め込みデータから言語の語彙を読み込む（初回のみ解析する）
+func loadLanguage(lang string) (*language, error) {
+	loadOnce.Do(func() {
+		languages, loadErr = parseLanguages()
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	l, ok := languages[lang]
+	if !ok {
+		return nil, fmt.Errorf("コーパスが未対応の言語: %s (ja/en)", lang)
+	}
+	return l, nil
+}
+
+// parseLanguages 全言語のデータファイルを解析
+func parseLanguages() (map[string]*language, error) {
+	var err error
+	if emoji, err = readLines("data/emoji.txt"); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*language, len(formats))
+	for name, f := range formats {
+		l := &language{format: f}
+		lists := []struct {
+			file string
+			dst  *[]string
+		}{
+			{"nouns.txt", &l.nouns},
+			{"verbs.txt", &l.verbs},
+			{"adjectives.txt", &l.adjectives},
+			{"patterns.txt", &l.patterns},
+			{"titles.txt", &l.titles},
+		}
+		for _, list := range lists {
+			if *list.dst, err = readLines(path.Join("data", name, list.file)); err != nil {
+				return nil, err
+			}
+		}
+
+		lines, err := readLines(path.Join("data", name, "corpus.txt"))
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			l.examples = append(l.examples, strings.Fields(line))
+		}
+		result[name] = l
+	}
+	return result, nil
+}
+
+// readLines 空行とコメント行を除いた行を読み込む（空のファイルはエラー）
+func readLines(name string) ([]string, error) {
+	f, err := data.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("コーパス読み込みエラー: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("コーパス読み込みエラー (%s): %w", name, err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("コーパス %s に項目がありません", name)
+	}
+	return lines, nil
+}
+
+// chain 例文から学習したマルコフ連鎖（初回のみ構築する）
+func (l *language) chain() *chain {
+	l.chainOnce.Do(func() {
+		l.markov = newChain(l.examples)
+	})
+	return l.markov
+}
+
+// join トークン列を1文にする（英語では句読点の前に空白を入れない）
+func (l *language) join(tokens []string) string {
+	if l.tokenSep == "" {
+		return strings.Join(tokens, "")
+	}
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 && !isPunctuation(t) {
+			b.WriteString(l.tokenSep)
+		}
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// isPunctuation 前の語に続けて書く句読点か
+func isPunctuation(token string) bool {
+	switch token {
+	case ".", ",", "?", "!", ":", ";":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+// internal/testdata/corpus/markov.go
+package corpus
+
+import (
+	"math/rand"
+)
+
+// chainOrder 次のトークンを決めるのに使う直前のトークン数
+const chainOrder = 2
+
+// sentenceEnd 文の終わりを表す遷移先
+const sentenceEnd = ""
+
+// chain トークン単位の2次マルコフ連鎖
+//
+// 遷移先は出現回数分だけ重複して持つため、一様に選ぶと頻度に比例した確率になる。
+// 遷移先の順序は例文の順序で決まり、同じ乱数列からは同じ文が生成される。
+type chain struct {
+	next map[[chainOrder]string][]string
+}
+
+// newChain 例文（トークン列）から連鎖を構築
+func newChain(examples [][]string) *chain {
+	c := &chain{next: make(map[[chainOrder]string][]string)}
+	for _, tokens := range examples {
+		var state [chainOrder]string
+		for _, t := range tokens {
+			c.next[state] = append(c.next[state], t)
+			copy(state[:], state[1:])
+			state[chainOrder-1] = t
+		}
+		c.next[state] = append(c.next[state], sentenceEnd)
+	}
+	return c
+}
+
+// generate 文頭から文末まで遷移してトークン列を返す（最大 max トークン）
+func (c *chain) generate(r *rand.Rand, max int) []string {
+	var state [chainOrder]string
+	var tokens []string
+	for len(tokens) < max {
+		candidates := c.next[state]
+		if len(candidates) == 0 {
+			break
+		}
+		t := candidates[r.Intn(len(candidates))]
+		if t == sentenceEnd {
+			break
+		}
+		tokens = append(tokens, t)
+		copy(state[:], state[1:])
+		state[chainOrder-1] = t
+	}
+	return tokens
+}
@@ -0,0 +1,175 @@
+// internal/testdata/distribution/distribution.go
+package distribution
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Kind 分布の種類
+type Kind string
+
+const (
+	Uniform     Kind = "uniform"     // 一様分布
+	Zipf        Kind = "zipf"        // べき乗則（少数の要素に集中）
+	Normal      Kind = "normal"      // 正規分布（範囲内で切り詰め）
+	Exponential Kind = "exponential" // 指数分布（範囲内で切り詰め）
+)
+
+// 各分布の既定パラメータ
+const (
+	DefaultZipfS      = 1.1
+	DefaultNormalMean = 0.5
+	DefaultNormalSD   = 0.15
+	DefaultExpRate    = 5.0
+)
+
+// Distribution 範囲 [0, n) から整数を選ぶ確率分布の設定
+//
+// ゼロ値は一様分布。Zipf・指数分布では小さい値ほど選ばれやすく、
+// 「先に生成された少数のユーザー・投稿・タグに集中する」偏りを表現する。
+type Distribution struct {
+	Kind Kind `yaml:"kind"`
+
+	// S Zipf の指数（1より大きい値。大きいほど上位に集中）
+	S float64 `yaml:"s"`
+	// Mean, StdDev 正規分布の平均と標準偏差（範囲に対する比率 0.0-1.0）
+	Mean   float64 `yaml:"mean"`
+	StdDev float64 `yaml:"stddev"`
+	// Rate 指数分布の減衰率（大きいほど先頭に集中）
+	Rate float64 `yaml:"rate"`
+}
+
+// Validate 設定値の検証
+func (d Distribution) Validate() error {
+	switch d.Kind {
+	case "", Uniform:
+	case Zipf:
+		if d.S != 0 && d.S <= 1 {
+			return fmt.Errorf("zipf の指数 s は 1 より大きい必要があります: %v", d.S)
+		}
+	case Normal:
+		if d.Mean < 0 || d.Mean > 1 {
+			return fmt.Errorf("normal の mean は 0.0-1.0 の範囲で指定してください: %v", d.Mean)
+		}
+		if d.StdDev < 0 {
+			return fmt.Errorf("normal の stddev は 0 以上で指定してください: %v", d.StdDev)
+		}
+	case Exponential:
+		if d.Rate < 0 {
+			return fmt.Errorf("exponential の rate は 0 以上で指定してください: %v", d.Rate)
+		}
+	default:
+		return fmt.Errorf("未知の分布: %s", d.Kind)
+	}
+	return nil
+}
+
+// String ログ出力用の表現
+func (d Distribution) String() string {
+	d = d.withDefaults()
+	switch d.Kind {
+	case Zipf:
+		return fmt.Sprintf("zipf(s=%.2f)", d.S)
+	case Normal:
+		return fmt.Sprintf("normal(mean=%.2f, stddev=%.2f)", d.Mean, d.StdDev)
+	case Exponential:
+		return fmt.Sprintf("exponential(rate=%.2f)", d.Rate)
+	default:
+		return "uniform"
+	}
+}
+
+// withDefaults 未指定のパラメータに既定値を設定
+func (d Distribution) withDefaults() Distribution {
+	switch d.Kind {
+	case "":
+		d.Kind = Uniform
+	case Zipf:
+		if d.S == 0 {
+			d.S = DefaultZipfS
+		}
+	case Normal:
+		if d.Mean == 0 && d.StdDev == 0 {
+			d.Mean = DefaultNormalMean
+		}
+		if d.StdDev == 0 {
+			d.StdDev = DefaultNormalSD
+		}
+	case Exponential:
+		if d.Rate == 0 {
+			d.Rate = DefaultExpRate
+		}
+	}
+	return d
+}
+
+// Sampler 分布に従って値を選ぶ
+//
+// 乱数源を共有するため、同じシードなら同じ順序で同じ値を返す。
+type Sampler struct {
+	dist  Distribution
+	rand  *rand.Rand
+	zipf  *rand.Zipf
+	zipfN int
+}
+
+// NewSampler サンプラーを作成
+func NewSampler(d Distribution, r *rand.Rand) *Sampler {
+	return &Sampler{dist: d.withDefaults(), rand: r}
+}
+
+// Intn [0, n) の整数を返す
+func (s *Sampler) Intn(n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	switch s.dist.Kind {
+	case Zipf:
+		// 範囲が変わったときだけ生成器を作り直す
+		if s.zipf == nil || s.zipfN != n {
+			s.zipf = rand.NewZipf(s.rand, s.dist.S, 1, uint64(n-1))
+			s.zipfN = n
+		}
+		return int(s.zipf.Uint64())
+	case Normal:
+		return s.scale(s.truncated(func() float64 {
+			return s.dist.Mean + s.rand.NormFloat64()*s.dist.StdDev
+		}), n)
+	case Exponential:
+		return s.scale(s.truncated(func() float64 {
+			return s.rand.ExpFloat64() / s.dist.Rate
+		}), n)
+	default:
+		return s.rand.Intn(n)
+	}
+}
+
+// Between [min, max] の整数を返す
+func (s *Sampler) Between(min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + s.Intn(max-min+1)
+}
+
+// truncated [0, 1) に収まるまで再抽選（極端なパラメータでも停止するよう上限付き）
+func (s *Sampler) truncated(sample func() float64) float64 {
+	for i := 0; i < 100; i++ {
+		if x := sample(); x >= 0 && x < 1 {
+			return x
+		}
+	}
+	return math.Min(math.Max(sample(), 0), math.Nextafter(1, 0))
+}
+
+// scale [0, 1) の値を [0, n) の整数に変換
+func (s *Sampler) scale(x float64, n int) int {
+	i := int(x * float64(n))
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
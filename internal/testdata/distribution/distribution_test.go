@@ -0,0 +1,137 @@
+// internal/testdata/distribution/distribution_test.go
+package distribution
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		dist    Distribution
+		wantErr bool
+	}{
+		{Distribution{}, false},
+		{Distribution{Kind: Uniform}, false},
+		{Distribution{Kind: Zipf}, false},
+		{Distribution{Kind: Zipf, S: 1.5}, false},
+		{Distribution{Kind: Zipf, S: 1}, true},
+		{Distribution{Kind: Normal, Mean: 0.3, StdDev: 0.1}, false},
+		{Distribution{Kind: Normal, Mean: 1.5}, true},
+		{Distribution{Kind: Normal, StdDev: -0.1}, true},
+		{Distribution{Kind: Exponential, Rate: 3}, false},
+		{Distribution{Kind: Exponential, Rate: -1}, true},
+		{Distribution{Kind: "pareto"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.dist.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.Validate() = %v, エラーの期待値 %v", tt.dist, err, tt.wantErr)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		dist Distribution
+		want string
+	}{
+		{Distribution{}, "uniform"},
+		{Distribution{Kind: Zipf}, "zipf(s=1.10)"},
+		{Distribution{Kind: Normal}, "normal(mean=0.50, stddev=0.15)"},
+		{Distribution{Kind: Normal, Mean: 0.2}, "normal(mean=0.20, stddev=0.15)"},
+		{Distribution{Kind: Exponential, Rate: 8}, "exponential(rate=8.00)"},
+	}
+	for _, tt := range tests {
+		if got := tt.dist.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, 期待値 %q", tt.dist, got, tt.want)
+		}
+	}
+}
+
+// allKinds 各分布の代表的な設定
+var allKinds = []Distribution{
+	{},
+	{Kind: Zipf, S: 1.2},
+	{Kind: Normal, Mean: 0.5, StdDev: 0.2},
+	{Kind: Normal, Mean: 1, StdDev: 2}, // 範囲外が多い極端な設定
+	{Kind: Exponential, Rate: 5},
+	{Kind: Exponential, Rate: 0.01},
+}
+
+func TestIntnRange(t *testing.T) {
+	for _, d := range allKinds {
+		s := NewSampler(d, rand.New(rand.NewSource(1)))
+		for _, n := range []int{0, 1, 2, 10, 1000} {
+			for i := 0; i < 1000; i++ {
+				if v := s.Intn(n); v < 0 || (n > 0 && v >= n) || (n <= 1 && v != 0) {
+					t.Fatalf("%s: Intn(%d) = %d が範囲外です", d, n, v)
+				}
+			}
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	for _, d := range allKinds {
+		s := NewSampler(d, rand.New(rand.NewSource(1)))
+		for i := 0; i < 1000; i++ {
+			if v := s.Between(3, 7); v < 3 || v > 7 {
+				t.Fatalf("%s: Between(3, 7) = %d が範囲外です", d, v)
+			}
+		}
+		if v := s.Between(5, 5); v != 5 {
+			t.Errorf("%s: Between(5, 5) = %d", d, v)
+		}
+		if v := s.Between(5, 2); v != 5 {
+			t.Errorf("%s: Between(5, 2) = %d（max <= min は min）", d, v)
+		}
+	}
+}
+
+func TestSameSeedSameSequence(t *testing.T) {
+	for _, d := range allKinds {
+		a := NewSampler(d, rand.New(rand.NewSource(42)))
+		b := NewSampler(d, rand.New(rand.NewSource(42)))
+		for i := 0; i < 100; i++ {
+			n := 10 + i%3 // 範囲が変わっても同じ順序になること
+			if x, y := a.Intn(n), b.Intn(n); x != y {
+				t.Fatalf("%s: 同じシードで %d 回目の値が異なります: %d / %d", d, i, x, y)
+			}
+		}
+	}
+}
+
+// histogram [0, n) から count 回選んだ値の出現回数
+func histogram(d Distribution, n, count int) []int {
+	s := NewSampler(d, rand.New(rand.NewSource(7)))
+	h := make([]int, n)
+	for i := 0; i < count; i++ {
+		h[s.Intn(n)]++
+	}
+	return h
+}
+
+func TestShape(t *testing.T) {
+	const n, count = 10, 20000
+
+	uniform := histogram(Distribution{}, n, count)
+	for i, c := range uniform {
+		if c < count/n*8/10 || c > count/n*12/10 {
+			t.Errorf("uniform: %d の出現回数 %d が偏っています", i, c)
+		}
+	}
+
+	// Zipf・指数分布は先頭に集中する
+	for _, d := range []Distribution{{Kind: Zipf}, {Kind: Exponential}} {
+		h := histogram(d, n, count)
+		if h[0] < count/3 || h[0] <= h[n-1]*5 {
+			t.Errorf("%s: 先頭に集中していません: %v", d, h)
+		}
+	}
+
+	// 正規分布は平均の付近に集中する
+	h := histogram(Distribution{Kind: Normal, Mean: 0.5, StdDev: 0.1}, n, count)
+	if h[5]+h[4] < count/2 || h[0]+h[n-1] > count/50 {
+		t.Errorf("normal: 平均の付近に集中していません: %v", h)
+	}
+}
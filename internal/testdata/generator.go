@@ -2,6 +2,7 @@
 package testdata
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
@@ -9,40 +10,238 @@ import (
 	"time"
 
 	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/password"
+	"go-db-performance-study/internal/slug"
+	"go-db-performance-study/internal/testdata/corpus"
+	"go-db-performance-study/internal/testdata/distribution"
 
 	"github.com/brianvoe/gofakeit/v6"
-	"github.com/schollz/progressbar/v3"
 	"gorm.io/gorm"
 )
 
 // DataGenerator テストデータ生成器
 type DataGenerator struct {
-	db     *gorm.DB
-	faker  *gofakeit.Faker
-	config GeneratorConfig
+	db       *gorm.DB
+	faker    *gofakeit.Faker
+	rand     *rand.Rand // faker と共有する乱数源（シード固定で再現可能）
+	config   GeneratorConfig
+	baseTime time.Time // 生成する日時の基準時刻
+	sample   samplers
+	text     *corpus.Generator // 文章の生成元（nil の場合は組み込みの定型文）
+	ctx      context.Context
+	stats    []PhaseStat
+	run      *GenerationRun // チェックポイントを記録する実行（nil の場合は記録しない）
+
+	// この実行で実際に挿入されたID（外部キーはここからのみ選ぶ）
+	userIDs *idPool
+	tagIDs  *idPool
+	postIDs *idPool
 }
 
 // GeneratorConfig 生成設定
+//
+// yaml タグはシナリオ定義ファイル（configs/scenarios.yaml）での項目名。
 type GeneratorConfig struct {
-	UserCount    int
-	PostCount    int
-	TagCount     int
-	CommentCount int
-	BatchSize    int
+	UserCount    int `yaml:"users"`
+	PostCount    int `yaml:"posts"`
+	TagCount     int `yaml:"tags"`
+	CommentCount int `yaml:"comments"`
+	BatchSize    int `yaml:"batch_size"` // 0 の場合は DefaultBatchSize
+
+	// Seed 乱数シード（0 の場合は実行ごとにランダム）
+	// 同じシードと件数で空のデータベースに生成すれば、同一のデータセットになる
+	Seed int64 `yaml:"seed"`
+	// BaseTime 日時生成の基準時刻（ゼロ値の場合、シード指定時は DefaultBaseTime、それ以外は現在時刻）
+	BaseTime time.Time `yaml:"base_time"`
+	// Locale 投稿・コメントの文章の言語（空の場合は日本語）
+	Locale Locale `yaml:"locale"`
+	// Text 文章の生成方式と長さの分布（mode が空または template の場合は組み込みの定型文）
+	Text corpus.Config `yaml:"text"`
+
+	// ReplyProbability 各コメントが返信を受ける確率（0 の場合は返信を生成しない）
+	ReplyProbability float64 `yaml:"reply_probability"`
+	// MaxReplyDepth 返信の最大深さ（ルートコメントが深さ0、0 の場合は DefaultMaxReplyDepth）
+	MaxReplyDepth int `yaml:"max_reply_depth"`
+	// ReplyBranchWeights 返信を受けるコメント1件あたりの返信数の重み（i番目が i+1 件）
+	ReplyBranchWeights []int `yaml:"reply_branch_weights"`
+
+	// Distributions 関係ごとの分布（ゼロ値は一様分布）
+	Distributions Distributions `yaml:"distributions"`
+	// MaxTagsPerPost 投稿あたりの最大タグ数（0 の場合は DefaultMaxTagsPerPost）
+	MaxTagsPerPost int `yaml:"max_tags_per_post"`
+	// MaxViewCount 閲覧数の上限（0 の場合は DefaultMaxViewCount）
+	MaxViewCount int `yaml:"max_view_count"`
+
+	// StatusWeights 投稿・コメントのステータスの重み（全て 0 の場合は既定値）
+	StatusWeights StatusWeights `yaml:"status_weights"`
+	// DateRanges 作成日時などを生成する範囲（0 の項目は既定値）
+	DateRanges DateRanges `yaml:"date_ranges"`
+
+	// Scenario 実行記録に残すシナリオ名（シナリオ単位のクリーンアップに使う）
+	Scenario string `yaml:"-"`
+
+	// Workers 並行して挿入するワーカー数（各ワーカーが専用の接続を使う。0 の場合は 1）
+	Workers int `yaml:"-"`
+
+	// InsertMethod 行の書き込み方法（空の場合は CreateInBatches による複数行 INSERT）
+	InsertMethod InsertMethod `yaml:"-"`
+	// DisableIndexes 各フェーズの間はセカンダリインデックスを削除し、完了後に再作成
+	DisableIndexes bool `yaml:"-"`
+	// DisableChecks 挿入中は unique_checks / foreign_key_checks を無効化
+	DisableChecks bool `yaml:"-"`
 }
 
+// Distributions 関係ごとの分布設定
+type Distributions struct {
+	PostAuthor    distribution.Distribution `yaml:"post_author"`    // 投稿者の選択（ユーザーあたりの投稿数）
+	CommentPost   distribution.Distribution `yaml:"comment_post"`   // コメント先の投稿（投稿あたりのコメント数）
+	CommentAuthor distribution.Distribution `yaml:"comment_author"` // コメント投稿者の選択
+	Tag           distribution.Distribution `yaml:"tag"`            // タグの選択（タグあたりの投稿数）
+	TagsPerPost   distribution.Distribution `yaml:"tags_per_post"`  // 投稿あたりのタグ数（1〜MaxTagsPerPost）
+	ViewCount     distribution.Distribution `yaml:"view_count"`     // 閲覧数（0〜MaxViewCount）
+}
+
+// Validate 全ての分布設定を検証
+func (d Distributions) Validate() error {
+	fields := []struct {
+		name string
+		dist distribution.Distribution
+	}{
+		{"PostAuthor", d.PostAuthor},
+		{"CommentPost", d.CommentPost},
+		{"CommentAuthor", d.CommentAuthor},
+		{"Tag", d.Tag},
+		{"TagsPerPost", d.TagsPerPost},
+		{"ViewCount", d.ViewCount},
+	}
+	for _, f := range fields {
+		if err := f.dist.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// SkewedDistributions 実際のブログに近い偏り（多作な著者・バズった投稿・人気タグ）のプリセット
+func SkewedDistributions() Distributions {
+	return Distributions{
+		PostAuthor:    distribution.Distribution{Kind: distribution.Zipf, S: 1.2},
+		CommentPost:   distribution.Distribution{Kind: distribution.Zipf, S: 1.1},
+		CommentAuthor: distribution.Distribution{Kind: distribution.Zipf, S: 1.3},
+		Tag:           distribution.Distribution{Kind: distribution.Zipf, S: 1.4},
+		TagsPerPost:   distribution.Distribution{Kind: distribution.Normal, Mean: 0.4, StdDev: 0.25},
+		ViewCount:     distribution.Distribution{Kind: distribution.Exponential, Rate: 8},
+	}
+}
+
+// 分布関連の既定値
+const (
+	DefaultMaxTagsPerPost = 5
+	DefaultMaxViewCount   = 1000
+	DefaultBatchSize      = 1000
+)
+
+// samplers 関係ごとのサンプラー
+type samplers struct {
+	postAuthor    *distribution.Sampler
+	commentPost   *distribution.Sampler
+	commentAuthor *distribution.Sampler
+	tag           *distribution.Sampler
+	tagsPerPost   *distribution.Sampler
+	viewCount     *distribution.Sampler
+}
+
+// newSamplers 分布設定からサンプラーを作成
+func newSamplers(d Distributions, r *rand.Rand) samplers {
+	return samplers{
+		postAuthor:    distribution.NewSampler(d.PostAuthor, r),
+		commentPost:   distribution.NewSampler(d.CommentPost, r),
+		commentAuthor: distribution.NewSampler(d.CommentAuthor, r),
+		tag:           distribution.NewSampler(d.Tag, r),
+		tagsPerPost:   distribution.NewSampler(d.TagsPerPost, r),
+		viewCount:     distribution.NewSampler(d.ViewCount, r),
+	}
+}
+
+// DefaultBaseTime シード指定時の既定の基準時刻
+var DefaultBaseTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
 // NewDataGenerator 新しいデータ生成器を作成
 func NewDataGenerator(db *gorm.DB, config GeneratorConfig) *DataGenerator {
+	baseTime := config.BaseTime
+	if baseTime.IsZero() {
+		if config.Seed != 0 {
+			baseTime = DefaultBaseTime
+		} else {
+			baseTime = time.Now()
+		}
+	}
+
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	config.StatusWeights.Posts = config.StatusWeights.Posts.withDefaults()
+	config.StatusWeights.Comments = config.StatusWeights.Comments.withDefaults()
+	config.DateRanges = config.DateRanges.withDefaults()
+	if config.Locale == "" {
+		config.Locale = LocaleJa
+	}
+	if config.MaxTagsPerPost <= 0 {
+		config.MaxTagsPerPost = DefaultMaxTagsPerPost
+	}
+	if config.MaxViewCount <= 0 {
+		config.MaxViewCount = DefaultMaxViewCount
+	}
+
+	config.Text = config.Text.WithDefaults()
+
+	// 設定の誤りは Validate で報告されるため、ここでは定型文にフォールバックする
+	var text *corpus.Generator
+	if config.Text.Mode.UsesCorpus() {
+		var err error
+		if text, err = corpus.New(string(config.Locale), config.Text); err != nil {
+			log.Printf("文章コーパスの初期化エラー（定型文を使います）: %v", err)
+		}
+	}
+
+	faker := gofakeit.New(config.Seed)
+
 	return &DataGenerator{
-		db:     db,
-		faker:  gofakeit.New(0),
-		config: config,
+		db:       db,
+		faker:    faker,
+		rand:     faker.Rand,
+		config:   config,
+		baseTime: baseTime,
+		sample:   newSamplers(config.Distributions, faker.Rand),
+		text:     text,
+		ctx:      context.Background(),
+		userIDs:  newIDPool("ユーザー"),
+		tagIDs:   newIDPool("タグ"),
+		postIDs:  newIDPool("投稿"),
 	}
 }
 
+// WithContext キャンセル可能なコンテキストを設定（キャンセル時は全ワーカーを停止）
+func (g *DataGenerator) WithContext(ctx context.Context) *DataGenerator {
+	g.ctx = ctx
+	return g
+}
+
 // GenerateAll 全データを生成
 func (g *DataGenerator) GenerateAll() error {
 	log.Println("=== テストデータ生成開始 ===")
+	log.Printf("シード: %d, 基準時刻: %s", g.config.Seed, g.baseTime.Format(time.RFC3339))
+	if err := g.config.Validate(); err != nil {
+		return fmt.Errorf("生成設定エラー: %w", err)
+	}
+	g.logDistributions()
+
+	if err := g.startRun(false); err != nil {
+		return err
+	}
 
 	if err := g.GenerateUsers(); err != nil {
 		return fmt.Errorf("ユーザー生成エラー: %w", err)
@@ -64,6 +263,11 @@ func (g *DataGenerator) GenerateAll() error {
 		return fmt.Errorf("タグ関連付けエラー: %w", err)
 	}
 
+	if err := g.finishRun(); err != nil {
+		return err
+	}
+
+	g.logStats()
 	log.Println("=== テストデータ生成完了 ===")
 	return nil
 }
@@ -72,27 +276,53 @@ func (g *DataGenerator) GenerateAll() error {
 func (g *DataGenerator) GenerateUsers() error {
 	log.Printf("ユーザー生成中: %d件", g.config.UserCount)
 
-	bar := progressbar.Default(int64(g.config.UserCount))
+	firstID, err := g.beginPhase("users", g.config.UserCount)
+	if err != nil {
+		return err
+	}
 	batchSize := g.config.BatchSize
 
-	for i := 0; i < g.config.UserCount; i += batchSize {
-		users := make([]models.User, 0, batchSize)
-
-		for j := 0; j < batchSize && i+j < g.config.UserCount; j++ {
+	err = g.runPhase("users", g.config.UserCount, batchCount(g.config.UserCount, batchSize), func(b *DataGenerator, index int) (batchJob, error) {
+		offset := index * batchSize
+		n := min(batchSize, g.config.UserCount-offset)
+		users := make([]models.User, 0, n)
+
+		for j := 0; j < n; j++ {
+			id := firstID + uint(offset+j)
+			name := b.faker.Name()
+			createdAt := b.randomPastTime(g.config.DateRanges.UserDays)
+			// パスワードはここで1回だけハッシュ化し、挿入時は User.BeforeCreate を飛ばす
+			// （INSERT・LOAD DATA のどちらも SkipHooks でフックを実行しない。重複キーでやり直しても再ハッシュしない）
+			hashedPassword, err := password.Default().Hash("password123")
+			if err != nil {
+				return batchJob{}, err
+			}
 			users = append(users, models.User{
-				Name:            g.faker.Name(),
-				Email:           g.faker.Email(),
-				Password:        "password123",
-				EmailVerifiedAt: g.randomTimePointer(),
+				ID:              id,
+				Name:            name,
+				Email:           uniqueEmail(name, id, b.faker.DomainName()),
+				Password:        hashedPassword,
+				EmailVerifiedAt: b.randomTimePointer(),
+				CreatedAt:       createdAt,
+				UpdatedAt:       createdAt,
 			})
-			bar.Add(1)
 		}
 
-		if err := g.db.CreateInBatches(users, batchSize).Error; err != nil {
-			return err
+		emails := make([]*string, len(users))
+		for i := range users {
+			emails[i] = &users[i].Email
 		}
+		return batchJob{rows: n, insert: func(tx *gorm.DB) error {
+			// ハッシュ化済みのパスワードを再びハッシュ化しないよう、フックを飛ばして挿入する
+			tx = tx.Session(&gorm.Session{SkipHooks: true})
+			return g.insertUnique(tx, users, uniqueColumn{table: "users", column: "email", values: emails, rewrite: rewriteEmail})
+		}}, nil
+	})
+	if err != nil {
+		return err
 	}
 
+	g.userIDs.addRange(firstID, g.config.UserCount)
 	return nil
 }
 
@@ -100,14 +330,21 @@ func (g *DataGenerator) GenerateUsers() error {
 func (g *DataGenerator) GenerateTags() error {
 	log.Printf("タグ生成中: %d件", g.config.TagCount)
 
+	firstID, err := g.beginPhase("tags", g.config.TagCount)
+	if err != nil {
+		return err
+	}
+
+	// 再開時に中断前の自分自身のタグを既存扱いしないよう、開始IDより前だけを見る
 	var existing []models.Tag
-	if err := g.db.Select("name").Find(&existing).Error; err != nil {
+	if err := g.db.WithContext(g.ctx).Select("name").Where("id < ?", firstID).Find(&existing).Error; err != nil {
 		return fmt.Errorf("既存タグ取得エラー: %w", err)
 	}
 
+	// 名前の一意インデックスは大文字小文字を区別しないため、小文字で比較する
 	used := make(map[string]bool)
 	for _, t := range existing {
-		used[t.Name] = true
+		used[strings.ToLower(t.Name)] = true
 	}
 
 	techTags := []string{
@@ -123,44 +360,70 @@ func (g *DataGenerator) GenerateTags() error {
 		"#6f42c1", "#e83e8c", "#fd7e14", "#20c997", "#6c757d",
 	}
 
+	// 名前と slug の重複を避けるためタグは1つの乱数源で順に生成する
+	b := g.forBatch("tags", 0)
 	tags := make([]models.Tag, 0, g.config.TagCount)
+	slugs := make(map[string]bool)
+	tagSlug := func(name string) string {
+		base := slug.Tags.MakeASCII(name)
+		candidate := base
+		for n := 2; slugs[strings.ToLower(candidate)]; n++ {
+			candidate = slug.WithSuffix(base, n, slug.Tags.MaxLength)
+		}
+		slugs[strings.ToLower(candidate)] = true
+		return candidate
+	}
 
 	for _, name := range techTags {
 		if len(tags) >= g.config.TagCount {
 			break
 		}
-		if !used[name] {
-			used[name] = true
+		if key := strings.ToLower(name); !used[key] {
+			used[key] = true
+			createdAt := b.randomPastTime(g.config.DateRanges.UserDays)
 			tags = append(tags, models.Tag{
-				Name:  name,
-				Color: colors[rand.Intn(len(colors))],
+				ID:        firstID + uint(len(tags)),
+				Name:      name,
+				Slug:      tagSlug(name),
+				Color:     colors[b.rand.Intn(len(colors))],
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
 			})
 		}
 	}
 
 	for len(tags) < g.config.TagCount {
-		name := g.faker.Word() + " " + g.faker.Word()
-		if !used[name] {
-			used[name] = true
+		name := b.faker.Word() + " " + b.faker.Word()
+		if key := strings.ToLower(name); !used[key] {
+			used[key] = true
+			createdAt := b.randomPastTime(g.config.DateRanges.UserDays)
 			tags = append(tags, models.Tag{
-				Name:  name,
-				Color: colors[rand.Intn(len(colors))],
+				ID:        firstID + uint(len(tags)),
+				Name:      name,
+				Slug:      tagSlug(name),
+				Color:     colors[b.rand.Intn(len(colors))],
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
 			})
 		}
 	}
 
-	bar := progressbar.Default(int64(len(tags)))
-	for i := 0; i < len(tags); i += g.config.BatchSize {
-		end := i + g.config.BatchSize
-		if end > len(tags) {
-			end = len(tags)
-		}
-		if err := g.db.CreateInBatches(tags[i:end], g.config.BatchSize).Error; err != nil {
-			return fmt.Errorf("タグ挿入エラー: %w", err)
+	batchSize := g.config.BatchSize
+	err = g.runPhase("tags", len(tags), batchCount(len(tags), batchSize), func(_ *DataGenerator, index int) (batchJob, error) {
+		chunk := tags[index*batchSize : min((index+1)*batchSize, len(tags))]
+		slugs := make([]*string, len(chunk))
+		for i := range chunk {
+			slugs[i] = &chunk[i].Slug
 		}
-		bar.Add(end - i)
+		return batchJob{rows: len(chunk), insert: func(tx *gorm.DB) error {
+			return g.insertUnique(tx, chunk, uniqueColumn{table: "tags", column: "slug", values: slugs, rewrite: rewriteSlug(slug.Tags)})
+		}}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("タグ挿入エラー: %w", err)
 	}
 
+	g.tagIDs.addRange(firstID, len(tags))
 	return nil
 }
 
@@ -168,106 +431,99 @@ func (g *DataGenerator) GenerateTags() error {
 func (g *DataGenerator) GeneratePosts() error {
 	log.Printf("投稿生成中: %d件", g.config.PostCount)
 
-	bar := progressbar.Default(int64(g.config.PostCount))
-	batchSize := g.config.BatchSize
+	if err := g.userIDs.require("投稿の生成"); err != nil {
+		return err
+	}
 
-	templates := g.getPostTemplates()
-	statuses := []models.PostStatus{
-		models.PostStatusDraft,
-		models.PostStatusPublished,
-		models.PostStatusArchived,
+	firstID, err := g.beginPhase("posts", g.config.PostCount)
+	if err != nil {
+		return err
 	}
-	weights := []int{20, 70, 10}
+	batchSize := g.config.BatchSize
 
-	for i := 0; i < g.config.PostCount; i += batchSize {
-		posts := make([]models.Post, 0, batchSize)
+	templates := g.getPostTemplates()
+	statuses, weights := g.config.StatusWeights.Posts.entries()
 
-		for j := 0; j < batchSize && i+j < g.config.PostCount; j++ {
-			template := templates[rand.Intn(len(templates))]
-			status := g.weightedRandomStatus(statuses, weights)
-			title := g.generateTitle(template.Category)
-			body := g.generateBody(template)
+	err = g.runPhase("posts", g.config.PostCount, batchCount(g.config.PostCount, batchSize), func(b *DataGenerator, index int) (batchJob, error) {
+		offset := index * batchSize
+		n := min(batchSize, g.config.PostCount-offset)
+		posts := make([]models.Post, 0, n)
 
-			// Excerpt: Body の先頭 100 文字（UTF-8 安全）
-			excerpt := string([]rune(body)[:min(100, len([]rune(body)))])
+		for j := 0; j < n; j++ {
+			id := firstID + uint(offset+j)
+			template := templates[b.rand.Intn(len(templates))]
+			status := b.weightedRandomStatus(statuses, weights)
+			title := b.generateTitle(template.Category)
+			body := b.generateBody(template)
 
+			createdAt := b.randomPastTime(g.config.DateRanges.PostDays)
 			posts = append(posts, models.Post{
-				UserID:    uint(rand.Intn(g.config.UserCount) + 1),
+				ID:        id,
+				UserID:    g.userIDs.pickWith(b.sample.postAuthor),
 				Title:     title,
 				Body:      body,
-				Excerpt:   excerpt,
+				Excerpt:   models.PostExcerpt(body),
 				Status:    status,
-				ViewCount: uint(rand.Intn(1000)),
-				CreatedAt: g.randomPastTime(365),
-				Slug:      g.generateUniqueSlug(title), // ユニーク slug
+				ViewCount: b.randomViewCount(),
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Slug:      uniqueSlug(title, id), // ID を含むためバッチ間でも重複しない
 			})
-			bar.Add(1)
 		}
 
-		if err := g.db.CreateInBatches(posts, batchSize).Error; err != nil {
-			return fmt.Errorf("投稿挿入エラー: %w", err)
+		slugs := make([]*string, len(posts))
+		for i := range posts {
+			slugs[i] = &posts[i].Slug
 		}
+		return batchJob{rows: n, insert: func(tx *gorm.DB) error {
+			return g.insertUnique(tx, posts, uniqueColumn{table: "posts", column: "slug", values: slugs, rewrite: rewriteSlug(slug.Posts)})
+		}}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("投稿挿入エラー: %w", err)
 	}
 
+	g.postIDs.addRange(firstID, g.config.PostCount)
 	return nil
 }
 
 func (g *DataGenerator) GenerateComments() error {
 	log.Printf("コメント生成中: %d件", g.config.CommentCount)
 
-	bar := progressbar.Default(int64(g.config.CommentCount))
-	batchSize := g.config.BatchSize
-
-	// DB に存在する Post ID を取得
-	var postIDs []uint
-	if err := g.db.Model(&models.Post{}).Pluck("id", &postIDs).Error; err != nil {
-		return fmt.Errorf("投稿ID取得エラー: %w", err)
-	}
-	if len(postIDs) == 0 {
-		return fmt.Errorf("コメント生成用の投稿が存在しません")
+	if err := g.postIDs.require("コメントの生成"); err != nil {
+		return err
 	}
-
-	commentTemplates := []string{
-		"とても参考になりました！ありがとうございます。",
-		"詳しい説明ありがとうございます。実際に試してみます。",
-		"この方法は知りませんでした。勉強になります。",
-		"素晴らしい記事ですね。続編も期待しています。",
-		"実装例があるとより理解しやすいかもしれません。",
-		"同じような問題に遭遇していたので、とても助かりました。",
-		"別のアプローチも紹介していただけると嬉しいです。",
-		"初心者にもわかりやすい説明で良かったです。",
+	if err := g.userIDs.require("コメントの生成"); err != nil {
+		return err
 	}
 
-	statuses := []models.CommentStatus{
-		models.CommentStatusPending,
-		models.CommentStatusApproved,
-		models.CommentStatusSpam,
+	thread := g.threadSettings()
+	if thread.probability > 0 {
+		log.Printf("返信生成: 確率=%.2f, 最大深さ=%d, 分岐の重み=%v", thread.probability, thread.maxDepth, thread.branchWeights)
 	}
-	weights := []int{20, 70, 10}
-
-	for i := 0; i < g.config.CommentCount; i += batchSize {
-		comments := make([]models.Comment, 0, batchSize)
-
-		for j := 0; j < batchSize && i+j < g.config.CommentCount; j++ {
-			template := commentTemplates[rand.Intn(len(commentTemplates))]
-			body := template + "\n\n" + g.faker.Sentence(10)
 
-			comments = append(comments, models.Comment{
-				PostID:    postIDs[rand.Intn(len(postIDs))], // DB から取得した ID を使用
-				UserID:    uint(rand.Intn(g.config.UserCount) + 1),
-				Body:      body,
-				Status:    g.weightedRandomCommentStatus(statuses, weights),
-				CreatedAt: g.randomPastTime(180),
-			})
-			bar.Add(1)
-		}
-
-		if err := g.db.CreateInBatches(comments, batchSize).Error; err != nil {
-			return err
-		}
+	firstID, err := g.beginPhase("comments", g.config.CommentCount)
+	if err != nil {
+		return err
 	}
+	batchSize := g.config.BatchSize
 
-	return nil
+	// バッチサイズ分の件数を1グループとし、ルートコメントとその返信ツリーを生成する
+	// グループ内は親から順に挿入し、グループ同士は並行して挿入する
+	return g.runPhase("comments", g.config.CommentCount, batchCount(g.config.CommentCount, batchSize), func(b *DataGenerator, index int) (batchJob, error) {
+		offset := index * batchSize
+		budget := min(batchSize, g.config.CommentCount-offset)
+		waves := b.buildCommentGroup(thread, budget, firstID+uint(offset))
+
+		return batchJob{rows: budget, insert: func(tx *gorm.DB) error {
+			for _, wave := range waves {
+				if err := g.insertRows(tx, wave); err != nil {
+					return err
+				}
+			}
+			return nil
+		}}, nil
+	})
 }
 
 // ----------------- ヘルパー: 重み付きランダムでコメントステータス生成 -----------------
@@ -277,7 +533,7 @@ func (g *DataGenerator) weightedRandomCommentStatus(statuses []models.CommentSta
 		total += w
 	}
 
-	r := rand.Intn(total)
+	r := g.rand.Intn(total)
 	for i, w := range weights {
 		if r < w {
 			return statuses[i]
@@ -289,51 +545,143 @@ func (g *DataGenerator) weightedRandomCommentStatus(statuses []models.CommentSta
 }
 
 // ----------------- 投稿とタグ関連付け -----------------
+
+// postTagRowsPerStatement 1つの INSERT に含める post_tags の最大行数（プレースホルダ上限 65535 の内側）
+const postTagRowsPerStatement = 10000
+
+// postTag 投稿とタグの関連
+type postTag struct {
+	PostID uint
+	TagID  uint
+}
+
+// AssignTagsToPosts 投稿にタグを関連付け、タグの投稿数を更新
+//
+// 投稿やタグをモデルとして読み込まず、IDプールから選んだ組をバッチごとに
+// INSERT IGNORE でまとめて挿入する。投稿あたりのタグ数は TagsPerPost 分布に従い、
+// 同じ投稿に同じタグが重複しないよう非復元抽出で選ぶ。
 func (g *DataGenerator) AssignTagsToPosts() error {
 	log.Println("投稿とタグの関連付け中...")
 
-	var posts []models.Post
-	if err := g.db.Find(&posts).Error; err != nil {
+	if err := g.postIDs.require("投稿とタグの関連付け"); err != nil {
 		return err
 	}
+	if err := g.tagIDs.require("投稿とタグの関連付け"); err != nil {
+		return err
+	}
+
+	total := g.postIDs.len()
+	batchSize := g.config.BatchSize
+
+	err := g.runPhase("post_tags", total, batchCount(total, batchSize), func(b *DataGenerator, index int) (batchJob, error) {
+		offset := index * batchSize
+		n := min(batchSize, total-offset)
+
+		pairs := make([]postTag, 0, n*g.config.MaxTagsPerPost)
+		for j := 0; j < n; j++ {
+			postID := g.postIDs.at(offset + j)
+			for _, tagID := range b.pickTags() {
+				pairs = append(pairs, postTag{PostID: postID, TagID: tagID})
+			}
+		}
 
-	var tags []models.Tag
-	if err := g.db.Find(&tags).Error; err != nil {
+		return batchJob{rows: n, insert: func(tx *gorm.DB) error {
+			return insertPostTags(tx, pairs)
+		}}, nil
+	})
+	if err != nil {
 		return err
 	}
 
-	bar := progressbar.Default(int64(len(posts)))
+	return g.updateTagPostCounts()
+}
 
-	for _, post := range posts {
-		tagCount := rand.Intn(5) + 1
-		selectedTags := make([]models.Tag, 0, tagCount)
+// pickTags 1投稿分のタグIDを重複なく選ぶ
+//
+// 偏った分布では同じタグが続けて選ばれやすいため、試行回数に上限を設け、
+// 上限に達したらそれまでに選べたタグだけを使う。
+func (g *DataGenerator) pickTags() []uint {
+	want := min(g.sample.tagsPerPost.Between(1, g.config.MaxTagsPerPost), g.tagIDs.len())
+
+	picked := make([]uint, 0, want)
+	seen := make(map[uint]bool, want)
+	for attempt := 0; len(picked) < want && attempt < want*20; attempt++ {
+		id := g.tagIDs.pickWith(g.sample.tag)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		picked = append(picked, id)
+	}
+	return picked
+}
 
-		for i := 0; i < tagCount; i++ {
-			tag := tags[rand.Intn(len(tags))]
-			selectedTags = append(selectedTags, tag)
+// insertPostTags 投稿とタグの組を INSERT IGNORE でまとめて挿入（既存の組は無視）
+func insertPostTags(tx *gorm.DB, pairs []postTag) error {
+	for start := 0; start < len(pairs); start += postTagRowsPerStatement {
+		chunk := pairs[start:min(start+postTagRowsPerStatement, len(pairs))]
+
+		var sb strings.Builder
+		sb.WriteString("INSERT IGNORE INTO `post_tags` (`post_id`, `tag_id`) VALUES ")
+		args := make([]interface{}, 0, len(chunk)*2)
+		for i, p := range chunk {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString("(?,?)")
+			args = append(args, p.PostID, p.TagID)
 		}
 
-		if err := g.db.Model(&post).Association("Tags").Replace(selectedTags); err != nil {
+		if err := tx.Exec(sb.String(), args...).Error; err != nil {
 			return err
 		}
-
-		bar.Add(1)
 	}
+	return nil
+}
 
+// updateTagPostCounts 全タグの投稿数を post_tags の件数から1文で更新
+func (g *DataGenerator) updateTagPostCounts() error {
+	log.Println("タグの投稿数を更新中...")
+
+	err := g.db.WithContext(g.ctx).Exec("UPDATE `tags` t " +
+		"LEFT JOIN (SELECT tag_id, COUNT(*) AS cnt FROM `post_tags` GROUP BY tag_id) pt ON pt.tag_id = t.id " +
+		"SET t.post_count = COALESCE(pt.cnt, 0)").Error
+	if err != nil {
+		return fmt.Errorf("タグの投稿数更新エラー: %w", err)
+	}
 	return nil
 }
 
 // ----------------- ヘルパーメソッド -----------------
+// logDistributions 分布設定をログ出力
+func (g *DataGenerator) logDistributions() {
+	d := g.config.Distributions
+	log.Printf("分布: 投稿者=%s, コメント先=%s, コメント投稿者=%s, タグ=%s, タグ数=%s(最大%d), 閲覧数=%s(最大%d)",
+		d.PostAuthor, d.CommentPost, d.CommentAuthor, d.Tag,
+		d.TagsPerPost, g.config.MaxTagsPerPost, d.ViewCount, g.config.MaxViewCount)
+	if t := g.config.Text; g.text != nil {
+		log.Printf("文章: %s (%s), 本文=%s文, 段落=%s文, コメント=%s文, 返信=%s文, 絵文字=%.0f%%",
+			t.Mode, g.config.Locale, t.PostSentences, t.ParagraphSentences, t.CommentSentences, t.ReplySentences, t.Emoji*100)
+	}
+}
+
+// randomViewCount 分布に従った閲覧数
+func (g *DataGenerator) randomViewCount() uint {
+	return uint(g.sample.viewCount.Intn(g.config.MaxViewCount))
+}
+
 func (g *DataGenerator) randomTimePointer() *time.Time {
-	if rand.Float32() < 0.8 {
-		t := g.randomPastTime(30)
+	if g.rand.Float32() < 0.8 {
+		t := g.randomPastTime(g.config.DateRanges.VerifiedDays)
 		return &t
 	}
 	return nil
 }
 
 func (g *DataGenerator) randomPastTime(days int) time.Time {
-	return time.Now().AddDate(0, 0, -rand.Intn(days))
+	// 日単位に加えて秒単位でもばらつかせる（ミリ秒精度で保存されるため丸めておく）
+	offset := time.Duration(g.rand.Int63n(int64(24*time.Hour/time.Second))) * time.Second
+	return g.baseTime.AddDate(0, 0, -g.rand.Intn(days)).Add(-offset)
 }
 
 func (g *DataGenerator) weightedRandomStatus(statuses []models.PostStatus, weights []int) models.PostStatus {
@@ -342,7 +690,7 @@ func (g *DataGenerator) weightedRandomStatus(statuses []models.PostStatus, weigh
 		total += w
 	}
 
-	r := rand.Intn(total)
+	r := g.rand.Intn(total)
 	for i, w := range weights {
 		if r < w {
 			return statuses[i]
@@ -360,44 +708,29 @@ type PostTemplate struct {
 }
 
 func (g *DataGenerator) getPostTemplates() []PostTemplate {
-	return []PostTemplate{
-		{
-			Category: "Go言語",
-			Content: []string{
-				"Go言語でWebアプリケーションを開発する方法について詳しく解説します。",
-				"基本的なHTTPサーバーの作成から、ルーティング、ミドルウェアの実装まで。",
-				"実際のコード例も交えて、実践的な内容をお届けします。",
-			},
-		},
-		{
-			Category: "データベース",
-			Content: []string{
-				"データベース設計のベストプラクティスをまとめました。",
-				"正規化、インデックス、クエリ最適化について。",
-				"実際のパフォーマンス測定結果も含めて紹介します。",
-			},
-		},
-	}
+	return g.config.Locale.texts().postTemplates
 }
 
 func (g *DataGenerator) generateTitle(category string) string {
-	prefixes := []string{
-		"初心者向け", "実践的な", "効率的な", "最新の", "詳解",
-		"完全攻略", "基礎から学ぶ", "プロが教える", "実例で学ぶ",
+	if g.text != nil {
+		return g.text.Title(g.rand)
 	}
 
-	suffixes := []string{
-		"入門", "基礎講座", "実践ガイド", "チュートリアル", "まとめ",
-		"解説", "手順", "方法", "テクニック", "ノウハウ",
-	}
+	texts := g.config.Locale.texts()
+	prefixes := texts.titlePrefixes
+	suffixes := texts.titleSuffixes
 
-	prefix := prefixes[rand.Intn(len(prefixes))]
-	suffix := suffixes[rand.Intn(len(suffixes))]
+	prefix := prefixes[g.rand.Intn(len(prefixes))]
+	suffix := suffixes[g.rand.Intn(len(suffixes))]
 
 	return fmt.Sprintf("%s %s %s", prefix, category, suffix)
 }
 
 func (g *DataGenerator) generateBody(template PostTemplate) string {
+	if g.text != nil {
+		return g.text.Body(g.rand)
+	}
+
 	content := ""
 	for _, paragraph := range template.Content {
 		content += paragraph + "\n\n"
@@ -407,17 +740,6 @@ func (g *DataGenerator) generateBody(template PostTemplate) string {
 	return content
 }
 
-// ----------------- ユニーク slug 生成 -----------------
-func (g *DataGenerator) generateUniqueSlug(title string) string {
-	timestamp := time.Now().UnixNano()
-	randomNum := rand.Intn(10000)
-	slug := fmt.Sprintf("%s-%d-%d", title, timestamp, randomNum)
-	slug = strings.ToLower(slug)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "/", "-")
-	return slug
-}
-
 // ----------------- ユーティリティ -----------------
 func min(a, b int) int {
 	if a < b {
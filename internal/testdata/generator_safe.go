@@ -3,9 +3,14 @@ package testdata
 
 import (
 	"fmt"
-	"math/rand"
+	"log"
+	"time"
 
 	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/slug"
+	"go-db-performance-study/internal/textutil"
+
+	"gorm.io/gorm"
 )
 
 // -----------------------
@@ -14,6 +19,16 @@ import (
 
 // GenerateAllSafe 全データを生成（投稿生成時に文字化け対策済み）
 func (g *DataGenerator) GenerateAllSafe() error {
+	log.Printf("シード: %d, 基準時刻: %s", g.config.Seed, g.baseTime.Format(time.RFC3339))
+	if err := g.config.Validate(); err != nil {
+		return fmt.Errorf("生成設定エラー: %w", err)
+	}
+	g.logDistributions()
+
+	if err := g.startRun(true); err != nil {
+		return err
+	}
+
 	if err := g.GenerateUsers(); err != nil {
 		return fmt.Errorf("ユーザー生成エラー: %w", err)
 	}
@@ -34,64 +49,73 @@ func (g *DataGenerator) GenerateAllSafe() error {
 		return fmt.Errorf("タグ関連付けエラー: %w", err)
 	}
 
+	if err := g.finishRun(); err != nil {
+		return err
+	}
+
+	g.logStats()
 	return nil
 }
 
 // GeneratePostsSafe 投稿生成（文字列長・文字コードを安全に調整）
 func (g *DataGenerator) GeneratePostsSafe() error {
+	if err := g.userIDs.require("投稿の生成"); err != nil {
+		return err
+	}
+
+	firstID, err := g.beginPhase("posts", g.config.PostCount)
+	if err != nil {
+		return err
+	}
+
 	batchSize := g.config.BatchSize
-	for i := 0; i < g.config.PostCount; i += batchSize {
-		posts := make([]models.Post, 0, batchSize)
-		for j := 0; j < batchSize && i+j < g.config.PostCount; j++ {
-			post := g.generateSinglePost()
+	err = g.runPhase("posts", g.config.PostCount, batchCount(g.config.PostCount, batchSize), func(b *DataGenerator, index int) (batchJob, error) {
+		offset := index * batchSize
+		n := min(batchSize, g.config.PostCount-offset)
+		posts := make([]models.Post, 0, n)
+		for j := 0; j < n; j++ {
+			post := b.generateSinglePost(firstID + uint(offset+j))
 			posts = append(posts, post)
 		}
 
-		if err := g.db.CreateInBatches(posts, batchSize).Error; err != nil {
-			return err
+		slugs := make([]*string, len(posts))
+		for i := range posts {
+			slugs[i] = &posts[i].Slug
 		}
+		return batchJob{rows: n, insert: func(tx *gorm.DB) error {
+			return g.insertUnique(tx, posts, uniqueColumn{table: "posts", column: "slug", values: slugs, rewrite: rewriteSlug(slug.Posts)})
+		}}, nil
+	})
+	if err != nil {
+		return err
 	}
 
+	g.postIDs.addRange(firstID, g.config.PostCount)
 	return nil
 }
 
 // generateSinglePost 単一の投稿データを生成（文字コード安全）
-func (g *DataGenerator) generateSinglePost() models.Post {
+func (g *DataGenerator) generateSinglePost(id uint) models.Post {
 	templates := g.getPostTemplates()
-	template := templates[rand.Intn(len(templates))]
-
-	// タイトル・本文を utf8mb4 に収まるように調整
-	title := g.generateTitle(template.Category)
-	if len(title) > 255 {
-		title = title[:255]
-	}
+	template := templates[g.rand.Intn(len(templates))]
 
-	body := g.generateBody(template)
-	if len(body) > 65535 { // TEXT 型の上限
-		body = body[:65535]
-	}
-
-	excerpt := ""
-	if len(body) > 200 {
-		excerpt = body[:200]
-	} else {
-		excerpt = body
-	}
+	// タイトル・本文を列の上限に収まるように調整（文字の途中では切らない）
+	title := textutil.Truncate(g.generateTitle(template.Category), 255, "")
+	body := textutil.TruncateBytes(g.generateBody(template), 65535) // TEXT 型の上限
 
-	statuses := []models.PostStatus{
-		models.PostStatusDraft,
-		models.PostStatusPublished,
-		models.PostStatusArchived,
-	}
-	weights := []int{20, 70, 10}
+	statuses, weights := g.config.StatusWeights.Posts.entries()
 
+	createdAt := g.randomPastTime(g.config.DateRanges.PostDays)
 	return models.Post{
-		UserID:    uint(rand.Intn(g.config.UserCount) + 1),
+		ID:        id,
+		UserID:    g.userIDs.pickWith(g.sample.postAuthor),
 		Title:     title,
 		Body:      body,
-		Excerpt:   excerpt,
+		Excerpt:   models.PostExcerpt(body),
+		Slug:      uniqueSlug(title, id),
 		Status:    g.weightedRandomStatus(statuses, weights),
-		ViewCount: uint(rand.Intn(1000)),
-		CreatedAt: g.randomPastTime(365),
+		ViewCount: g.randomViewCount(),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
 	}
 }
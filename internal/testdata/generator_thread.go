@@ -0,0 +1,198 @@
+// internal/testdata/generator_thread.go
+package testdata
+
+import (
+	"math"
+	"time"
+
+	"go-db-performance-study/internal/models"
+	"go-db-performance-study/internal/moderation"
+)
+
+// 返信生成の既定値
+const (
+	DefaultMaxReplyDepth = 3
+)
+
+// DefaultReplyBranchWeights 返信数の既定の重み（1件:60, 2件:25, 3件:10, 4件:5）
+var DefaultReplyBranchWeights = []int{60, 25, 10, 5}
+
+// threadSettings 返信ツリー生成の設定（既定値適用済み）
+type threadSettings struct {
+	probability   float64
+	maxDepth      int
+	branchWeights []int
+	expectedSize  float64 // ルート1件あたりの期待ツリーサイズ
+}
+
+// threadSettings 生成設定から返信ツリーの設定を組み立てる
+func (g *DataGenerator) threadSettings() threadSettings {
+	t := threadSettings{
+		probability:   g.config.ReplyProbability,
+		maxDepth:      g.config.MaxReplyDepth,
+		branchWeights: g.config.ReplyBranchWeights,
+	}
+	if t.probability <= 0 {
+		return threadSettings{expectedSize: 1}
+	}
+	if t.probability > 1 {
+		t.probability = 1
+	}
+	if t.maxDepth <= 0 {
+		t.maxDepth = DefaultMaxReplyDepth
+	}
+	if len(t.branchWeights) == 0 {
+		t.branchWeights = DefaultReplyBranchWeights
+	}
+
+	// 返信数の期待値
+	total, weighted := 0, 0
+	for i, w := range t.branchWeights {
+		total += w
+		weighted += w * (i + 1)
+	}
+	meanBranch := float64(weighted) / float64(total)
+
+	// 1 + r + r^2 + ... + r^maxDepth（r: コメント1件あたりの期待返信数）
+	r := t.probability * meanBranch
+	t.expectedSize = 0
+	for d := 0; d <= t.maxDepth; d++ {
+		t.expectedSize += math.Pow(r, float64(d))
+	}
+
+	return t
+}
+
+// rootsFor 件数の枠からルートコメント数を決める
+func (t threadSettings) rootsFor(budget int) int {
+	roots := int(math.Round(float64(budget) / t.expectedSize))
+	if roots < 1 {
+		roots = 1
+	}
+	if roots > budget {
+		roots = budget
+	}
+	return roots
+}
+
+// buildCommentGroup 件数の枠を使い切るまでルートコメントと返信ツリーを生成する
+//
+// 戻り値は挿入順に並んだ「波」の列で、IDは firstID から生成順に割り当てる。
+// 親は必ず子より前の波（別のINSERT）に入るため、ParentID は常に挿入済みの行を指す。
+func (g *DataGenerator) buildCommentGroup(thread threadSettings, budget int, firstID uint) [][]models.Comment {
+	var waves [][]models.Comment
+	used := 0
+	nextID := func() uint {
+		id := firstID + uint(used)
+		used++
+		return id
+	}
+
+	for used < budget {
+		roots := make([]models.Comment, thread.rootsFor(budget-used))
+		for i := range roots {
+			roots[i] = g.newComment(nextID(), nil, g.randomPastTime(g.config.DateRanges.CommentDays))
+		}
+		waves = append(waves, roots)
+
+		frontier := roots
+		for depth := 1; depth <= thread.maxDepth && used < budget && len(frontier) > 0; depth++ {
+			replies := make([]models.Comment, 0, len(frontier))
+
+			for i := range frontier {
+				if g.rand.Float64() >= thread.probability {
+					continue
+				}
+				branches := g.weightedRandomIndex(thread.branchWeights) + 1
+				for b := 0; b < branches && used < budget; b++ {
+					replies = append(replies, g.newComment(nextID(), &frontier[i], g.replyTime(frontier[i].CreatedAt)))
+				}
+			}
+
+			if len(replies) == 0 {
+				break
+			}
+			waves = append(waves, replies)
+			frontier = replies
+		}
+	}
+
+	return waves
+}
+
+// newComment コメントを1件生成（parent が nil ならルートコメント）
+func (g *DataGenerator) newComment(id uint, parent *models.Comment, createdAt time.Time) models.Comment {
+	statuses, weights := g.config.StatusWeights.Comments.entries()
+	comment := models.Comment{
+		ID:        id,
+		UserID:    g.userIDs.pickWith(g.sample.commentAuthor),
+		Status:    g.weightedRandomCommentStatus(statuses, weights),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	if parent == nil {
+		comment.PostID = g.postIDs.pickWith(g.sample.commentPost) // この実行で挿入した ID を使用
+		comment.Body = g.commentBody(false)
+	} else {
+		parentID := parent.ID
+		comment.PostID = parent.PostID // 返信は親と同じ投稿に属する
+		comment.ParentID = &parentID
+		comment.Body = g.commentBody(true)
+	}
+	// 重複判定用のハッシュは BeforeCreate でも計算されるが、path は AfterCreate で設定するため
+	// LOAD DATA（AfterCreate は動かない）でも入るよう、ここで計算しておく
+	comment.ContentHash = moderation.ContentHash(comment.Body)
+	parentPath := ""
+	if parent != nil {
+		parentPath = parent.Path
+	}
+	// 返信の段数は MaxReplyDepth まで（Validate で MaxThreadDepth 以下に制限）なので、長さの上限は超えない
+	comment.Path, _ = models.CommentPath(parentPath, id)
+
+	return comment
+}
+
+// commentBody コメントの本文（コーパスを使わない場合は定型文と英文のダミー）
+func (g *DataGenerator) commentBody(reply bool) string {
+	if g.text != nil {
+		if reply {
+			return g.text.Reply(g.rand)
+		}
+		return g.text.Comment(g.rand)
+	}
+
+	texts := g.config.Locale.texts()
+	if reply {
+		return texts.replies[g.rand.Intn(len(texts.replies))] + "\n\n" + g.faker.Sentence(6)
+	}
+	return texts.comments[g.rand.Intn(len(texts.comments))] + "\n\n" + g.faker.Sentence(10)
+}
+
+// replyTime 親コメントより後（最大 DateRanges.ReplyHours 時間後、基準時刻まで）の日時を返す
+func (g *DataGenerator) replyTime(parentCreatedAt time.Time) time.Time {
+	window := time.Duration(g.config.DateRanges.ReplyHours) * time.Hour
+	t := parentCreatedAt.Add(time.Duration(g.rand.Int63n(int64(window/time.Second))+1) * time.Second)
+	if t.After(g.baseTime) {
+		return g.baseTime
+	}
+	return t
+}
+
+// weightedRandomIndex 重みに従ってインデックスを選ぶ
+func (g *DataGenerator) weightedRandomIndex(weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	r := g.rand.Intn(total)
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+
+	return 0
+}
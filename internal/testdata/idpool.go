@@ -0,0 +1,75 @@
+// internal/testdata/idpool.go
+package testdata
+
+import (
+	"fmt"
+	"sort"
+
+	"go-db-performance-study/internal/testdata/distribution"
+)
+
+// idRange 連続したIDの範囲（両端を含む）
+type idRange struct {
+	First uint
+	Last  uint
+}
+
+// idPool 実際に挿入されたIDを範囲の集合として保持する
+//
+// 外部キーには既存データや AUTO_INCREMENT の状態に関係なく、
+// このプールに登録済みのIDだけを使う。大量のIDも範囲単位で保持するため省メモリ。
+type idPool struct {
+	entity string
+	ranges []idRange
+	starts []int // 各範囲の先頭が全体の何番目か（二分探索用）
+	count  int
+}
+
+// newIDPool IDプールを作成
+func newIDPool(entity string) *idPool {
+	return &idPool{entity: entity}
+}
+
+// add 範囲を追加（直前の範囲と連続していれば結合）
+func (p *idPool) add(first, last uint) {
+	if n := len(p.ranges); n > 0 && p.ranges[n-1].Last+1 == first {
+		p.ranges[n-1].Last = last
+	} else {
+		p.ranges = append(p.ranges, idRange{First: first, Last: last})
+		p.starts = append(p.starts, p.count)
+	}
+	p.count += int(last-first) + 1
+}
+
+// addRange 挿入が完了した連続ID（first から n 件）を登録
+func (p *idPool) addRange(first uint, n int) {
+	if n <= 0 {
+		return
+	}
+	p.add(first, first+uint(n)-1)
+}
+
+// len 登録済みID数
+func (p *idPool) len() int {
+	return p.count
+}
+
+// at i番目（0始まり）のIDを返す
+func (p *idPool) at(i int) uint {
+	k := sort.Search(len(p.starts), func(k int) bool { return p.starts[k] > i }) - 1
+	return p.ranges[k].First + uint(i-p.starts[k])
+}
+
+// pickWith 分布に従ってIDを1つ選ぶ（挿入順の早いIDほど選ばれやすい分布もある）
+func (p *idPool) pickWith(s *distribution.Sampler) uint {
+	return p.at(s.Intn(p.count))
+}
+
+// require 依存データが空なら分かりやすいエラーを返す
+func (p *idPool) require(dependent string) error {
+	if p.count == 0 {
+		return fmt.Errorf("%sには%sが必要ですが、この実行で生成された%sがありません（%sの生成を先に実行してください）",
+			dependent, p.entity, p.entity, p.entity)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// internal/testdata/locale.go
+package testdata
+
+import "fmt"
+
+// Locale 生成する文章の言語
+type Locale string
+
+const (
+	LocaleJa Locale = "ja" // 日本語（既定）
+	LocaleEn Locale = "en" // 英語
+)
+
+// Validate 言語の検証
+func (l Locale) Validate() error {
+	if _, ok := localeTexts[l]; ok || l == "" {
+		return nil
+	}
+	return fmt.Errorf("未対応の言語: %s (ja/en)", l)
+}
+
+// texts 言語ごとの文章テンプレートを取得（未指定の場合は日本語）
+func (l Locale) texts() *textSet {
+	if t, ok := localeTexts[l]; ok {
+		return t
+	}
+	return localeTexts[LocaleJa]
+}
+
+// textSet 1つの言語の文章テンプレート
+type textSet struct {
+	postTemplates []PostTemplate
+	titlePrefixes []string
+	titleSuffixes []string
+	comments      []string
+	replies       []string
+}
+
+var localeTexts = map[Locale]*textSet{
+	LocaleJa: {
+		postTemplates: []PostTemplate{
+			{
+				Category: "Go言語",
+				Content: []string{
+					"Go言語でWebアプリケーションを開発する方法について詳しく解説します。",
+					"基本的なHTTPサーバーの作成から、ルーティング、ミドルウェアの実装まで。",
+					"実際のコード例も交えて、実践的な内容をお届けします。",
+				},
+			},
+			{
+				Category: "データベース",
+				Content: []string{
+					"データベース設計のベストプラクティスをまとめました。",
+					"正規化、インデックス、クエリ最適化について。",
+					"実際のパフォーマンス測定結果も含めて紹介します。",
+				},
+			},
+		},
+		titlePrefixes: []string{
+			"初心者向け", "実践的な", "効率的な", "最新の", "詳解",
+			"完全攻略", "基礎から学ぶ", "プロが教える", "実例で学ぶ",
+		},
+		titleSuffixes: []string{
+			"入門", "基礎講座", "実践ガイド", "チュートリアル", "まとめ",
+			"解説", "手順", "方法", "テクニック", "ノウハウ",
+		},
+		comments: []string{
+			"とても参考になりました！ありがとうございます。",
+			"詳しい説明ありがとうございます。実際に試してみます。",
+			"この方法は知りませんでした。勉強になります。",
+			"素晴らしい記事ですね。続編も期待しています。",
+			"実装例があるとより理解しやすいかもしれません。",
+			"同じような問題に遭遇していたので、とても助かりました。",
+			"別のアプローチも紹介していただけると嬉しいです。",
+			"初心者にもわかりやすい説明で良かったです。",
+		},
+		replies: []string{
+			"返信ありがとうございます。",
+			"私も同じ意見です。",
+			"補足すると、設定によっては挙動が変わることがあります。",
+			"その点について詳しく教えていただけますか？",
+			"なるほど、そういう考え方もありますね。",
+			"試してみたところ、うまく動きました！",
+		},
+	},
+	LocaleEn: {
+		postTemplates: []PostTemplate{
+			{
+				Category: "Go",
+				Content: []string{
+					"This article explains how to build web applications in Go.",
+					"We cover everything from a basic HTTP server to routing and middleware.",
+					"Practical code examples are included throughout.",
+				},
+			},
+			{
+				Category: "Databases",
+				Content: []string{
+					"A summary of best practices for database design.",
+					"Covers normalization, indexing and query optimization.",
+					"Real performance measurements are included as well.",
+				},
+			},
+		},
+		titlePrefixes: []string{
+			"Beginner's", "Practical", "Efficient", "Modern", "In-Depth",
+			"Complete", "Fundamentals of", "Expert", "Hands-On",
+		},
+		titleSuffixes: []string{
+			"Introduction", "Basics", "Guide", "Tutorial", "Summary",
+			"Explained", "Walkthrough", "How-To", "Techniques", "Tips",
+		},
+		comments: []string{
+			"Very helpful, thank you!",
+			"Thanks for the detailed explanation. I'll give it a try.",
+			"I didn't know about this approach. Learned a lot.",
+			"Great article, looking forward to the next one.",
+			"A full implementation example would make this even clearer.",
+			"I ran into the same problem, so this really helped.",
+			"It would be great to see an alternative approach too.",
+			"Easy to follow even for beginners.",
+		},
+		replies: []string{
+			"Thanks for the reply.",
+			"I agree with you.",
+			"To add to this, the behavior can change depending on the settings.",
+			"Could you elaborate on that point?",
+			"I see, that's another way to look at it.",
+			"I tried it and it worked!",
+		},
+	},
+}
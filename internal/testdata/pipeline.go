@@ -0,0 +1,285 @@
+// internal/testdata/pipeline.go
+package testdata
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-db-performance-study/internal/bulkload"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/schollz/progressbar/v3"
+	"gorm.io/gorm"
+)
+
+// InsertMethod 行の書き込み方法
+type InsertMethod string
+
+const (
+	InsertMethodBatch    InsertMethod = "insert"    // CreateInBatches による複数行 INSERT
+	InsertMethodLoadData InsertMethod = "load-data" // CSV をストリーミングして LOAD DATA LOCAL INFILE
+)
+
+// Validate 書き込み方法の検証
+func (m InsertMethod) Validate() error {
+	switch m {
+	case "", InsertMethodBatch, InsertMethodLoadData:
+		return nil
+	}
+	return fmt.Errorf("未知の書き込み方法: %s (insert/load-data)", m)
+}
+
+// batchJob 1バッチ分の挿入処理（producer が生成し consumer が実行する）
+type batchJob struct {
+	index  int
+	rows   int
+	insert func(tx *gorm.DB) error
+}
+
+// produceFunc バッチ番号に対応する行を生成する
+//
+// b はバッチ専用の乱数源を持つ生成器で、実行順序やワーカー数に関係なく
+// 同じバッチ番号からは同じ行が生成される。
+type produceFunc func(b *DataGenerator, index int) (batchJob, error)
+
+// PhaseStat フェーズ（テーブル）ごとの挿入実績
+type PhaseStat struct {
+	Phase   string
+	Rows    int64
+	Elapsed time.Duration
+}
+
+// RowsPerSecond 1秒あたりの挿入行数
+func (s PhaseStat) RowsPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Rows) / s.Elapsed.Seconds()
+}
+
+// runPhase 行の生成と挿入をワーカープールで並行実行する
+//
+// バッチ番号を producer に配り、生成されたジョブを容量制限付きのチャネル経由で
+// consumer に渡す。consumer はそれぞれ専用の接続を確保し、ジョブ単位のトランザクションで挿入する。
+// いずれかのワーカーでエラーが起きると全ワーカーをキャンセルし、最初のエラーを返す。
+// 実行記録がある場合は完了済みのバッチを飛ばし、挿入したバッチを同じトランザクションで記録する。
+func (g *DataGenerator) runPhase(phase string, total, batches int, produce produceFunc) error {
+	workers := g.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	done, err := g.completedBatches(phase)
+	if err != nil {
+		return err
+	}
+	skipped := 0
+	for _, rows := range done {
+		skipped += rows
+	}
+	if len(done) > 0 {
+		log.Printf("%s: 完了済みの %d/%d バッチ（%d件）をスキップします", phase, len(done), batches, skipped)
+	}
+
+	// インデックスの削除・再作成にかかる時間も挿入速度に含める
+	start := time.Now()
+	restoreIndexes := func() error { return nil }
+	if g.config.DisableIndexes && len(done) < batches {
+		restoreIndexes, err = bulkload.DisableIndexes(g.db, phase) // キャンセル後も再作成できるようコンテキストは付けない
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(g.ctx)
+	defer cancel()
+
+	var (
+		firstErr error
+		errOnce  sync.Once
+		inserted int64
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	bar := progressbar.Default(int64(total))
+	bar.Add(skipped)
+
+	indexes := make(chan int)
+	jobs := make(chan batchJob, workers*2)
+
+	// バッチ番号の配布
+	go func() {
+		defer close(indexes)
+		for i := 0; i < batches; i++ {
+			if _, ok := done[i]; ok {
+				continue
+			}
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// producer: 行の生成
+	var producers sync.WaitGroup
+	for p := 0; p < workers; p++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			for index := range indexes {
+				job, err := produce(g.forBatch(phase, index), index)
+				if err != nil {
+					fail(fmt.Errorf("%s バッチ%d の生成エラー: %w", phase, index, err))
+					return
+				}
+				job.index = index
+				select {
+				case jobs <- job:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		producers.Wait()
+		close(jobs)
+	}()
+
+	// consumer: 専用接続でバッチを挿入
+	var consumers sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			consume := func(conn *gorm.DB) error {
+				for job := range jobs {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					err := conn.Transaction(func(tx *gorm.DB) error {
+						if err := job.insert(tx); err != nil {
+							return err
+						}
+						return g.recordBatch(tx, phase, job.index, job.rows)
+					})
+					if err != nil {
+						return fmt.Errorf("%s 挿入エラー: %w", phase, err)
+					}
+					atomic.AddInt64(&inserted, int64(job.rows))
+					bar.Add(job.rows)
+				}
+				return nil
+			}
+			err := g.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+				if !g.config.DisableChecks {
+					return consume(conn)
+				}
+				// 接続を使い回すため、ワーカーの開始時に一度だけ無効化する。
+				// 失敗・中断後も元に戻してからプールへ返すよう、設定の変更はキャンセルされないコンテキストで行う
+				restorable := conn.WithContext(context.WithoutCancel(ctx))
+				return bulkload.WithoutChecks(restorable, func(*gorm.DB) error {
+					return consume(conn)
+				})
+			})
+			if err != nil {
+				fail(err)
+			}
+		}()
+	}
+	consumers.Wait()
+
+	// 失敗時もインデックスは必ず元に戻す
+	restoreErr := restoreIndexes()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := g.ctx.Err(); err != nil {
+		return err
+	}
+	if restoreErr != nil {
+		return restoreErr
+	}
+
+	stat := PhaseStat{Phase: phase, Rows: inserted, Elapsed: time.Since(start)}
+	g.stats = append(g.stats, stat)
+	log.Printf("%s: %d件 / %v (%.0f 行/秒, ワーカー数=%d)",
+		phase, stat.Rows, stat.Elapsed.Round(time.Millisecond), stat.RowsPerSecond(), workers)
+
+	return nil
+}
+
+// insertRows バッチの行を設定された方法で挿入
+func (g *DataGenerator) insertRows(tx *gorm.DB, rows interface{}) error {
+	switch g.config.InsertMethod {
+	case InsertMethodLoadData:
+		return bulkload.Load(tx, rows)
+	case "", InsertMethodBatch:
+		return tx.CreateInBatches(rows, g.config.BatchSize).Error
+	default:
+		return g.config.InsertMethod.Validate()
+	}
+}
+
+// forBatch バッチ専用の乱数源を持つ生成器のコピーを返す
+//
+// シード・フェーズ名・バッチ番号から乱数源を派生させるため、
+// ワーカー数や処理順に関係なく同じデータが生成される。
+func (g *DataGenerator) forBatch(phase string, index int) *DataGenerator {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d", g.config.Seed, phase, index)
+	seed := int64(h.Sum64())
+	if seed == 0 {
+		seed = 1 // 0 は gofakeit ではランダムシードの意味になる
+	}
+
+	b := *g
+	b.faker = gofakeit.NewUnlocked(seed)
+	b.rand = b.faker.Rand
+	b.sample = newSamplers(g.config.Distributions, b.rand)
+	return &b
+}
+
+// nextID テーブルの次に使うID（既存の最大ID + 1）を取得
+//
+// 生成器はIDを事前に割り当てて挿入するため、並列に挿入しても
+// 同じシードからは同じIDと外部キーが得られる。生成中は他の書き込みがない前提。
+func (g *DataGenerator) nextID(table string) (uint, error) {
+	var maxID uint
+	err := g.db.WithContext(g.ctx).Table(table).Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error
+	if err != nil {
+		return 0, fmt.Errorf("%s の最大ID取得エラー: %w", table, err)
+	}
+	return maxID + 1, nil
+}
+
+// batchCount 件数をバッチサイズで割った個数（切り上げ）
+func batchCount(total, batchSize int) int {
+	return (total + batchSize - 1) / batchSize
+}
+
+// Stats フェーズごとの挿入実績を取得
+func (g *DataGenerator) Stats() []PhaseStat {
+	return g.stats
+}
+
+// logStats フェーズごとの挿入実績をまとめて出力
+func (g *DataGenerator) logStats() {
+	log.Println("テーブル別の挿入速度:")
+	for _, s := range g.stats {
+		log.Printf("  %-10s %10d件 %12v %10.0f 行/秒", s.Phase, s.Rows, s.Elapsed.Round(time.Millisecond), s.RowsPerSecond())
+	}
+}
@@ -7,16 +7,19 @@ import (
 	"gorm.io/gorm"
 )
 
-// GenerateLargeDataset 大規模データセット生成
-func GenerateLargeDataset(db *gorm.DB) error {
-	config := testdata.GeneratorConfig{
+// LargeConfig 大規模データセットの生成設定
+func LargeConfig() testdata.GeneratorConfig {
+	return testdata.GeneratorConfig{
 		UserCount:    100000,
 		PostCount:    500000,
 		TagCount:     2000,
 		CommentCount: 5000000,
 		BatchSize:    5000,
 	}
+}
 
-	generator := testdata.NewDataGenerator(db, config)
+// GenerateLargeDataset 大規模データセット生成
+func GenerateLargeDataset(db *gorm.DB) error {
+	generator := testdata.NewDataGenerator(db, LargeConfig())
 	return generator.GenerateAll()
 }
@@ -6,16 +6,19 @@ import (
     "gorm.io/gorm"
 )
 
-// GenerateMediumDataset 中規模データセット生成
-func GenerateMediumDataset(db *gorm.DB) error {
-    config := testdata.GeneratorConfig{
+// MediumConfig 中規模データセットの生成設定
+func MediumConfig() testdata.GeneratorConfig {
+    return testdata.GeneratorConfig{
         UserCount:    10000,
         PostCount:    50000,
         TagCount:     500,
         CommentCount: 200000,
         BatchSize:    1000,
     }
-    
-    generator := testdata.NewDataGenerator(db, config)
+}
+
+// GenerateMediumDataset 中規模データセット生成
+func GenerateMediumDataset(db *gorm.DB) error {
+    generator := testdata.NewDataGenerator(db, MediumConfig())
     return generator.GenerateAll()
-}
\ No newline at end of file
+}
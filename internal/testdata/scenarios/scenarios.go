@@ -0,0 +1,175 @@
+// internal/testdata/scenarios/scenarios.go
+package scenarios
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"go-db-performance-study/internal/testdata"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile シナリオ定義ファイルの既定のパス
+const DefaultFile = "configs/scenarios.yaml"
+
+// Scenario 名前で参照できるデータセット定義
+type Scenario struct {
+	Name        string
+	Description string
+	Source      string // 定義元（組み込み or ファイルパス）
+	Config      testdata.GeneratorConfig
+}
+
+// builtinSource 組み込みシナリオの定義元
+const builtinSource = "組み込み"
+
+// builtins 組み込みシナリオ（YAML の base にも指定できる）
+var builtins = []Scenario{
+	{Name: "small", Description: "小規模（動作確認用）", Source: builtinSource, Config: SmallConfig()},
+	{Name: "medium", Description: "中規模", Source: builtinSource, Config: MediumConfig()},
+	{Name: "large", Description: "大規模（数時間かかる）", Source: builtinSource, Config: LargeConfig()},
+}
+
+// Catalog シナリオの一覧
+type Catalog struct {
+	scenarios map[string]Scenario
+}
+
+// Builtin 組み込みシナリオのみのカタログ
+func Builtin() *Catalog {
+	c := &Catalog{scenarios: map[string]Scenario{}}
+	for _, s := range builtins {
+		c.scenarios[s.Name] = s
+	}
+	return c
+}
+
+// LoadCatalog 組み込みシナリオに YAML ファイルの定義を加えたカタログを読み込む
+//
+// 既定のパスのファイルが存在しない場合は組み込みシナリオのみを返す。
+// ファイル側で組み込みと同じ名前を定義した場合はファイル側が優先される。
+func LoadCatalog(path string) (*Catalog, error) {
+	c := Builtin()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && path == DefaultFile {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("シナリオファイルの読み込みエラー: %w", err)
+	}
+
+	if err := c.parse(path, data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// scenarioFile シナリオ定義ファイルの構造
+type scenarioFile struct {
+	Scenarios map[string]yaml.Node `yaml:"scenarios"`
+}
+
+// definition 1シナリオ分の定義（生成設定の項目はそのまま GeneratorConfig に読み込む）
+type definition struct {
+	Description string `yaml:"description"`
+	// Base 元にする組み込みシナリオ名（指定した項目だけを上書きする）
+	Base string `yaml:"base"`
+
+	testdata.GeneratorConfig `yaml:",inline"`
+}
+
+// parse YAML を解析してカタログに追加
+func (c *Catalog) parse(source string, data []byte) error {
+	var file scenarioFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("YAML解析エラー: %w", err)
+	}
+
+	for name, node := range file.Scenarios {
+		// base の設定を先に入れておき、YAML に書かれた項目だけを上書きする
+		var head struct {
+			Base string `yaml:"base"`
+		}
+		if err := node.Decode(&head); err != nil {
+			return fmt.Errorf("シナリオ %s: %w", name, err)
+		}
+
+		def := definition{}
+		if head.Base != "" {
+			base, ok := builtinByName(head.Base)
+			if !ok {
+				return fmt.Errorf("シナリオ %s: 未知の base です: %s (small/medium/large)", name, head.Base)
+			}
+			def.GeneratorConfig = base.Config
+		}
+
+		if err := decodeStrict(&node, &def); err != nil {
+			return fmt.Errorf("シナリオ %s: %w", name, err)
+		}
+		if err := def.GeneratorConfig.Validate(); err != nil {
+			return fmt.Errorf("シナリオ %s: %w", name, err)
+		}
+
+		c.scenarios[name] = Scenario{
+			Name:        name,
+			Description: def.Description,
+			Source:      source,
+			Config:      def.GeneratorConfig,
+		}
+	}
+	return nil
+}
+
+// decodeStrict 未知の項目をエラーにして YAML ノードを読み込む（項目名の打ち間違いを検出する）
+func decodeStrict(node *yaml.Node, out interface{}) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(out)
+}
+
+// builtinByName 組み込みシナリオを名前で取得
+func builtinByName(name string) (Scenario, bool) {
+	for _, s := range builtins {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// Get 名前でシナリオを取得
+func (c *Catalog) Get(name string) (Scenario, error) {
+	s, ok := c.scenarios[name]
+	if !ok {
+		return Scenario{}, fmt.Errorf("未知のシナリオ: %s", name)
+	}
+	return s, nil
+}
+
+// List 全シナリオを名前順で取得
+func (c *Catalog) List() []Scenario {
+	list := make([]Scenario, 0, len(c.scenarios))
+	for _, s := range c.scenarios {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ConfigByName 組み込みシナリオ名から生成設定を取得
+func ConfigByName(name string) (testdata.GeneratorConfig, error) {
+	s, err := Builtin().Get(name)
+	if err != nil {
+		return testdata.GeneratorConfig{}, err
+	}
+	return s.Config, nil
+}
@@ -6,16 +6,19 @@ import (
     "gorm.io/gorm"
 )
 
-// GenerateSmallDataset 小規模データセット生成
-func GenerateSmallDataset(db *gorm.DB) error {
-    config := testdata.GeneratorConfig{
+// SmallConfig 小規模データセットの生成設定
+func SmallConfig() testdata.GeneratorConfig {
+    return testdata.GeneratorConfig{
         UserCount:    100,
         PostCount:    500,
         TagCount:     30,
         CommentCount: 1000,
         BatchSize:    100,
     }
-    
-    generator := testdata.NewDataGenerator(db, config)
+}
+
+// GenerateSmallDataset 小規模データセット生成
+func GenerateSmallDataset(db *gorm.DB) error {
+    generator := testdata.NewDataGenerator(db, SmallConfig())
     return generator.GenerateAll()
-}
\ No newline at end of file
+}
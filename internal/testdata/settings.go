@@ -0,0 +1,158 @@
+// internal/testdata/settings.go
+package testdata
+
+import (
+	"fmt"
+
+	"go-db-performance-study/internal/models"
+)
+
+// PostStatusWeights 投稿ステータスの重み（全て 0 の場合は既定値）
+type PostStatusWeights struct {
+	Draft     int `yaml:"draft"`
+	Published int `yaml:"published"`
+	Archived  int `yaml:"archived"`
+}
+
+// DefaultPostStatusWeights 投稿ステータスの既定の重み
+var DefaultPostStatusWeights = PostStatusWeights{Draft: 20, Published: 70, Archived: 10}
+
+// withDefaults 全て 0 なら既定値を返す
+func (w PostStatusWeights) withDefaults() PostStatusWeights {
+	if w == (PostStatusWeights{}) {
+		return DefaultPostStatusWeights
+	}
+	return w
+}
+
+// entries ステータスと重みの組
+func (w PostStatusWeights) entries() ([]models.PostStatus, []int) {
+	return []models.PostStatus{models.PostStatusDraft, models.PostStatusPublished, models.PostStatusArchived},
+		[]int{w.Draft, w.Published, w.Archived}
+}
+
+// CommentStatusWeights コメントステータスの重み（全て 0 の場合は既定値）
+type CommentStatusWeights struct {
+	Pending  int `yaml:"pending"`
+	Approved int `yaml:"approved"`
+	Spam     int `yaml:"spam"`
+	Deleted  int `yaml:"deleted"`
+}
+
+// DefaultCommentStatusWeights コメントステータスの既定の重み
+var DefaultCommentStatusWeights = CommentStatusWeights{Pending: 20, Approved: 70, Spam: 10}
+
+// withDefaults 全て 0 なら既定値を返す
+func (w CommentStatusWeights) withDefaults() CommentStatusWeights {
+	if w == (CommentStatusWeights{}) {
+		return DefaultCommentStatusWeights
+	}
+	return w
+}
+
+// entries ステータスと重みの組
+func (w CommentStatusWeights) entries() ([]models.CommentStatus, []int) {
+	return []models.CommentStatus{models.CommentStatusPending, models.CommentStatusApproved, models.CommentStatusSpam, models.CommentStatusDeleted},
+		[]int{w.Pending, w.Approved, w.Spam, w.Deleted}
+}
+
+// StatusWeights 投稿・コメントのステータス分布
+type StatusWeights struct {
+	Posts    PostStatusWeights    `yaml:"posts"`
+	Comments CommentStatusWeights `yaml:"comments"`
+}
+
+// DateRanges 日時を生成する範囲（基準時刻から遡る日数など。0 の場合は既定値）
+type DateRanges struct {
+	UserDays     int `yaml:"user_days"`     // ユーザー・タグの作成日時
+	PostDays     int `yaml:"post_days"`     // 投稿の作成日時
+	CommentDays  int `yaml:"comment_days"`  // ルートコメントの作成日時
+	ReplyHours   int `yaml:"reply_hours"`   // 親コメントから返信までの最大時間
+	VerifiedDays int `yaml:"verified_days"` // メール認証日時
+}
+
+// DefaultDateRanges 日時範囲の既定値
+var DefaultDateRanges = DateRanges{UserDays: 365, PostDays: 365, CommentDays: 180, ReplyHours: 72, VerifiedDays: 30}
+
+// withDefaults 未指定の項目に既定値を設定
+func (r DateRanges) withDefaults() DateRanges {
+	d := DefaultDateRanges
+	if r.UserDays > 0 {
+		d.UserDays = r.UserDays
+	}
+	if r.PostDays > 0 {
+		d.PostDays = r.PostDays
+	}
+	if r.CommentDays > 0 {
+		d.CommentDays = r.CommentDays
+	}
+	if r.ReplyHours > 0 {
+		d.ReplyHours = r.ReplyHours
+	}
+	if r.VerifiedDays > 0 {
+		d.VerifiedDays = r.VerifiedDays
+	}
+	return d
+}
+
+// Validate 生成設定の検証
+func (c GeneratorConfig) Validate() error {
+	if c.UserCount < 0 || c.PostCount < 0 || c.TagCount < 0 || c.CommentCount < 0 {
+		return fmt.Errorf("件数に負の値は指定できません")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("バッチサイズに負の値は指定できません: %d", c.BatchSize)
+	}
+	if c.ReplyProbability < 0 || c.ReplyProbability > 1 {
+		return fmt.Errorf("返信確率は 0.0-1.0 の範囲で指定してください: %v", c.ReplyProbability)
+	}
+	if c.MaxReplyDepth < 0 || c.MaxReplyDepth > models.MaxThreadDepth {
+		return fmt.Errorf("返信の最大深さは 0-%d の範囲で指定してください: %d", models.MaxThreadDepth, c.MaxReplyDepth)
+	}
+	if err := validateWeights("返信数の重み", c.ReplyBranchWeights); err != nil {
+		return err
+	}
+	if len(c.ReplyBranchWeights) > 0 && sum(c.ReplyBranchWeights) == 0 {
+		return fmt.Errorf("返信数の重みの合計は 1 以上にしてください: %v", c.ReplyBranchWeights)
+	}
+	if err := c.Distributions.Validate(); err != nil {
+		return fmt.Errorf("分布設定エラー: %w", err)
+	}
+	_, postWeights := c.StatusWeights.Posts.entries()
+	if err := validateWeights("投稿ステータスの重み", postWeights); err != nil {
+		return err
+	}
+	_, commentWeights := c.StatusWeights.Comments.entries()
+	if err := validateWeights("コメントステータスの重み", commentWeights); err != nil {
+		return err
+	}
+	if r := c.DateRanges; r.UserDays < 0 || r.PostDays < 0 || r.CommentDays < 0 || r.ReplyHours < 0 || r.VerifiedDays < 0 {
+		return fmt.Errorf("日時範囲に負の値は指定できません")
+	}
+	if err := c.Locale.Validate(); err != nil {
+		return err
+	}
+	if err := c.Text.Validate(); err != nil {
+		return fmt.Errorf("文章設定エラー: %w", err)
+	}
+	return c.InsertMethod.Validate()
+}
+
+// validateWeights 重みが負でないことを確認
+func validateWeights(name string, weights []int) error {
+	for _, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("%sに負の値は指定できません: %v", name, weights)
+		}
+	}
+	return nil
+}
+
+// sum 重みの合計
+func sum(weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	return total
+}
@@ -0,0 +1,236 @@
+// internal/testdata/unique.go
+package testdata
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-db-performance-study/internal/bulkload"
+	"go-db-performance-study/internal/slug"
+
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 一意な値の生成に関する設定
+const (
+	// uniqueLookupChunk 既存の値を照合する IN 句1回あたりの件数
+	uniqueLookupChunk = 1000
+	// maxUniqueRounds 書き換えた値を再照合する最大回数
+	maxUniqueRounds = 10
+	// maxInsertRetries 重複キーエラーで挿入をやり直す最大回数
+	maxInsertRetries = 3
+)
+
+// uniqueEmail 名前と事前割り当てのIDからメールアドレスを作る
+//
+// ローカル部の末尾に ID を36進数で付けるため、同じ実行の中ではバッチ間で
+// 調整しなくても重複しない（ID は実行をまたいでも重複しない）。
+func uniqueEmail(name string, id uint, domain string) string {
+	local := emailLocalPart(name)
+	if local == "" {
+		local = "user"
+	}
+	return fmt.Sprintf("%s.%s@%s", local, strconv.FormatUint(uint64(id), 36), strings.ToLower(domain))
+}
+
+// uniqueSlug タイトルと事前割り当てのIDから URL に使える slug を作る（ID を含むためバッチ間でも重複しない）
+//
+// 漢字など ASCII にできない語は除く（全て除かれた場合は slug.Posts.Fallback）。
+func uniqueSlug(title string, id uint) string {
+	suffix := "-" + strconv.FormatUint(uint64(id), 36)
+	return slug.Truncate(slug.Posts.MakeASCII(title), slug.Posts.MaxLength-len(suffix)) + suffix
+}
+
+// emailLocalPart 名前をメールアドレスのローカル部に使える ASCII の英小文字・数字とドットにする
+//
+// 互換分解（NFKD）してから結合文字を取り除くため、アクセント付きの文字は対応する ASCII になる。
+// それ以外の文字は区切りとして扱い、連続する区切りは1つのドットにまとめる。
+func emailLocalPart(name string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pending && b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			pending = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// O'Brien → obrien のように、アポストロフィは区切りにしない
+		default:
+			pending = true
+		}
+	}
+	return b.String()
+}
+
+// rewriteSlug 衝突した slug に連番を付ける（foo → foo-2）
+func rewriteSlug(scope slug.Scope) func(string, int) string {
+	return func(value string, n int) string {
+		return slug.WithSuffix(value, n, scope.MaxLength)
+	}
+}
+
+// rewriteEmail 衝突したメールアドレスのローカル部に連番を付ける（a@x → a-2@x）
+func rewriteEmail(value string, n int) string {
+	at := strings.LastIndexByte(value, '@')
+	if at < 0 {
+		return fmt.Sprintf("%s-%d", value, n)
+	}
+	return fmt.Sprintf("%s-%d%s", value[:at], n, value[at:])
+}
+
+// uniqueColumn 一意制約のある列と、これから挿入する行の値
+type uniqueColumn struct {
+	table  string
+	column string
+	// values 行の値へのポインタ（書き換えはこれを通して行う）
+	values []*string
+	// rewrite 元の値と連番（2, 3, ...）から別の候補を作る
+	rewrite func(value string, n int) string
+}
+
+// resolve 既存の行や同じバッチ内の値と重複する値を書き換え、書き換えた件数を返す
+//
+// 生成した値は小文字の ASCII なので、照合順序の大文字小文字の違いは小文字に揃えて比較する。
+//
+// locking が true の場合は FOR SHARE で照合し、スナップショットより後にコミットされた行も確認する。
+func (c uniqueColumn) resolve(tx *gorm.DB, locking bool) (int, error) {
+	taken := make(map[string]bool, len(c.values))
+	queued := map[*string]bool{}
+	var pending []*string
+	enqueue := func(v *string) {
+		if !queued[v] {
+			queued[v] = true
+			pending = append(pending, v)
+		}
+	}
+	for _, v := range c.values {
+		key := strings.ToLower(*v)
+		if taken[key] {
+			enqueue(v)
+			continue
+		}
+		taken[key] = true
+	}
+
+	check := c.values
+	originals := map[*string]string{}
+	attempts := map[*string]int{}
+	for round := 0; ; round++ {
+		existing, err := c.existing(tx, check, locking)
+		if err != nil {
+			return len(originals), err
+		}
+		for _, v := range check {
+			if existing[strings.ToLower(*v)] {
+				enqueue(v)
+			}
+		}
+		if len(pending) == 0 {
+			return len(originals), nil
+		}
+		if round >= maxUniqueRounds {
+			return len(originals), fmt.Errorf("%s.%s の重複を解消できません（%d件、例: %s）", c.table, c.column, len(pending), *pending[0])
+		}
+
+		// 重複した値を連番付きの候補に置き換え、次の周回で既存データと照合する
+		check = nil
+		for _, v := range pending {
+			if _, ok := originals[v]; !ok {
+				originals[v] = *v
+				attempts[v] = 1
+			}
+			for {
+				attempts[v]++
+				candidate := c.rewrite(originals[v], attempts[v])
+				if key := strings.ToLower(candidate); !taken[key] {
+					taken[key] = true
+					*v = candidate
+					break
+				}
+			}
+			check = append(check, v)
+		}
+		pending = nil
+		queued = map[*string]bool{}
+	}
+}
+
+// existing 値のうち、テーブルに既に存在するもの（小文字）
+func (c uniqueColumn) existing(tx *gorm.DB, values []*string, locking bool) (map[string]bool, error) {
+	found := make(map[string]bool)
+	for start := 0; start < len(values); start += uniqueLookupChunk {
+		end := start + uniqueLookupChunk
+		if end > len(values) {
+			end = len(values)
+		}
+		chunk := make([]string, 0, end-start)
+		for _, v := range values[start:end] {
+			chunk = append(chunk, *v)
+		}
+
+		q := tx.Table(c.table).Where(fmt.Sprintf("%s IN ?", c.column), chunk)
+		if locking {
+			q = q.Clauses(clause.Locking{Strength: "SHARE"})
+		}
+		var rows []string
+		if err := q.Pluck(c.column, &rows).Error; err != nil {
+			return nil, fmt.Errorf("%s.%s の重複確認エラー: %w", c.table, c.column, err)
+		}
+		for _, r := range rows {
+			found[strings.ToLower(r)] = true
+		}
+	}
+	return found, nil
+}
+
+// insertUnique 一意制約のある列を既存データと照合してから挿入する
+//
+// 照合後に他の接続が同じ値を挿入した場合など、重複キーエラーになったときは
+// セーブポイントまで戻し、最新のコミット済みの行と照合し直して maxInsertRetries 回までやり直す。
+// 生成を最後まで進めることを優先し、書き換えた件数はログに残す。
+func (g *DataGenerator) insertUnique(tx *gorm.DB, rows interface{}, columns ...uniqueColumn) error {
+	for attempt := 1; ; attempt++ {
+		for _, c := range columns {
+			n, err := c.resolve(tx, attempt > 1)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				log.Printf("%s.%s: 既存データと重複した %d件を書き換えました", c.table, c.column, n)
+			}
+		}
+
+		if err := tx.SavePoint("unique_insert").Error; err != nil {
+			return fmt.Errorf("セーブポイント作成エラー: %w", err)
+		}
+		err := g.insertRows(tx, rows)
+		if err == nil || !isDuplicateKey(err) || attempt > maxInsertRetries {
+			return err
+		}
+		log.Printf("重複キーのため挿入をやり直します (%d/%d): %v", attempt, maxInsertRetries, err)
+		if err := tx.RollbackTo("unique_insert").Error; err != nil {
+			return fmt.Errorf("セーブポイントへのロールバックエラー: %w", err)
+		}
+	}
+}
+
+// isDuplicateKey 一意制約違反のエラーか（LOAD DATA で行が読み飛ばされた場合も含む）
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return true
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey) || errors.Is(err, bulkload.ErrRowsSkipped)
+}
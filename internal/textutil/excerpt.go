@@ -0,0 +1,52 @@
+// internal/textutil/excerpt.go
+package textutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Excerpt テキストから maxRunes 文字以内の1行の抜粋を作る
+//
+// 空白・改行を1つの空白にまとめ、できるだけ語の区切り（英語は空白、日本語は
+// 行分割できる位置）で切り、末尾に Ellipsis を付ける（Ellipsis を含めて maxRunes に収める）。
+// 区切りが手前すぎる（半分より前）場合は書記素クラスタの境目で切る。
+// HTML・Markdown の本文は先に PlainText で記法を取り除くこと。
+func Excerpt(s string, maxRunes int) string {
+	plain := strings.Join(strings.Fields(s), " ")
+	if maxRunes <= 0 || utf8.RuneCountInString(plain) <= maxRunes {
+		return plain
+	}
+	budget := maxRunes - utf8.RuneCountInString(Ellipsis)
+	if budget <= 0 {
+		return Truncate(plain, maxRunes, "")
+	}
+
+	end, lastBreak, runes := 0, 0, 0
+	state := -1
+	for rest := plain; rest != ""; {
+		var cluster string
+		var boundaries int
+		cluster, rest, boundaries, state = uniseg.StepString(rest, state)
+		runes += utf8.RuneCountInString(cluster)
+		if runes > budget {
+			break
+		}
+		end += len(cluster)
+		if boundaries&uniseg.MaskLine != uniseg.LineDontBreak {
+			lastBreak = end
+		}
+	}
+	if lastBreak > end/2 {
+		end = lastBreak
+	}
+
+	// 区切りの空白や読点を残さない
+	cut := strings.TrimRightFunc(plain[:end], func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("、,:;・", r)
+	})
+	return cut + Ellipsis
+}
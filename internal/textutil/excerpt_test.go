@@ -0,0 +1,25 @@
+// internal/textutil/excerpt_test.go
+package textutil
+
+import "testing"
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		maxRunes int
+		want     string
+	}{
+		{"上限以内は空白をまとめるだけ", "hello\n\n  world", 20, "hello world"},
+		{"英語は語の区切りで切る", "the quick brown fox jumps", 15, "the quick..."},
+		{"区切りの読点を残さない", "今日は、晴れです。明日は雨です。", 7, "今日は..."},
+		{"上限が Ellipsis 以下なら Ellipsis を付けない", "abcdef", 2, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Excerpt(tt.s, tt.maxRunes); got != tt.want {
+				t.Errorf("Excerpt(%q, %d) = %q, 期待値 %q", tt.s, tt.maxRunes, got, tt.want)
+			}
+		})
+	}
+}
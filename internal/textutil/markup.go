@@ -0,0 +1,71 @@
+// internal/textutil/markup.go
+package textutil
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// HTML の除去に使うパターン
+var (
+	htmlHidden = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>|<!--.*?-->`)
+	htmlBlock  = regexp.MustCompile(`(?i)</?(br|p|div|li|ul|ol|tr|h[1-6]|blockquote|pre)\b[^>]*>`)
+	htmlTag    = regexp.MustCompile(`</?[a-zA-Z][^<>]*>`)
+)
+
+// StripHTML HTML のタグを除去し、文字参照（&amp; など）を元の文字に戻す
+func StripHTML(s string) string {
+	return html.UnescapeString(StripTags(s))
+}
+
+// StripTags HTML のタグだけを除去する（文字参照はそのまま）
+//
+// script・style の中身とコメントは捨て、段落や改行のタグは改行にする。
+// タグに見えない "<"（a < b など）はそのまま残す。
+func StripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return s
+	}
+	s = htmlHidden.ReplaceAllString(s, "")
+	s = htmlBlock.ReplaceAllString(s, "\n")
+	return htmlTag.ReplaceAllString(s, "")
+}
+
+// Markdown の除去に使うパターン（行単位のものは (?m) で行頭に一致させる）
+var (
+	mdFence    = regexp.MustCompile("(?m)^ {0,3}(```|~~~).*$")
+	mdRule     = regexp.MustCompile(`(?m)^ {0,3}(-( *-){2,}|\*( *\*){2,}|_( *_){2,}) *$`)
+	mdHeading  = regexp.MustCompile(`(?m)^ {0,3}#{1,6}[ \t]+`)
+	mdQuote    = regexp.MustCompile(`(?m)^ {0,3}(> ?)+`)
+	mdList     = regexp.MustCompile(`(?m)^[ \t]*([-*+]|\d{1,9}[.)])[ \t]+(\[[ xX]\][ \t]+)?`)
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink     = regexp.MustCompile(`\[([^\]]+)\](\([^)]*\)|\[[^\]]*\])`)
+	mdCode     = regexp.MustCompile("`+([^`\n]+)`+")
+	mdStrong   = regexp.MustCompile(`(\*\*|__)([^*_\n]+)(\*\*|__)`)
+	mdEmphasis = regexp.MustCompile(`\*([^*\n]+)\*|(^|[^\w])_([^_\n]+)_([^\w]|$)`)
+	mdStrike   = regexp.MustCompile(`~~([^~\n]+)~~`)
+)
+
+// StripMarkdown Markdown の記法を取り除き、本文の文字だけを残す
+//
+// 見出し・引用・リストの記号、強調、リンク（文字だけ残す）、画像（代替テキストを残す）、
+// インラインコードとコードブロックの囲みを除去する。snake_case のような語中の _ は残す。
+func StripMarkdown(s string) string {
+	s = mdFence.ReplaceAllString(s, "")
+	s = mdRule.ReplaceAllString(s, "")
+	s = mdHeading.ReplaceAllString(s, "")
+	s = mdQuote.ReplaceAllString(s, "")
+	s = mdList.ReplaceAllString(s, "")
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdCode.ReplaceAllString(s, "$1")
+	s = mdStrong.ReplaceAllString(s, "$2")
+	s = mdEmphasis.ReplaceAllString(s, "$1$2$3$4")
+	return mdStrike.ReplaceAllString(s, "$1")
+}
+
+// PlainText HTML と Markdown を取り除き、空白・改行を1つの空白にまとめる
+func PlainText(s string) string {
+	return strings.Join(strings.Fields(StripMarkdown(StripHTML(s))), " ")
+}
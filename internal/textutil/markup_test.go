@@ -0,0 +1,48 @@
+// internal/textutil/markup_test.go
+package textutil
+
+import "testing"
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"<p>Hello <b>world</b></p>", "\nHello world\n"},
+		{"a < b &amp;&amp; c > d", "a < b && c > d"},
+		{"<script>alert(1)</script>text<!-- note -->", "text"},
+		{"line1<br>line2", "line1\nline2"},
+	}
+	for _, tt := range tests {
+		if got := StripHTML(tt.s); got != tt.want {
+			t.Errorf("StripHTML(%q) = %q, 期待値 %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestStripMarkdown(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"# 見出し", "見出し"},
+		{"> 引用", "引用"},
+		{"- [x] 完了\n1. 番号", "完了\n番号"},
+		{"**太字** と *斜体* と ~~取消~~", "太字 と 斜体 と 取消"},
+		{"[リンク](https://example.com) と ![画像](a.png)", "リンク と 画像"},
+		{"`code` と snake_case_name", "code と snake_case_name"},
+		{"```go\nfmt.Println()\n```", "\nfmt.Println()\n"},
+	}
+	for _, tt := range tests {
+		if got := StripMarkdown(tt.s); got != tt.want {
+			t.Errorf("StripMarkdown(%q) = %q, 期待値 %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	s := "<h2>## タイトル</h2>\n<p>本文の **強調** と [リンク](/a)</p>"
+	if got, want := PlainText(s), "タイトル 本文の 強調 と リンク"; got != want {
+		t.Errorf("PlainText(%q) = %q, 期待値 %q", s, got, want)
+	}
+}
@@ -0,0 +1,102 @@
+// internal/textutil/truncate.go
+package textutil
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Ellipsis 省略したことを示す末尾の文字列
+const Ellipsis = "..."
+
+// Truncate maxRunes 文字（コードポイント数）以内に切り詰める
+//
+// MySQL の VARCHAR(n) と同じく文字数で数える。書記素クラスタ（絵文字の ZWJ 連結、
+// 結合文字付きの文字など）の途中では切らない。切り詰めた場合は tail を付け、
+// tail を含めて maxRunes 文字に収める（tail が maxRunes 文字に収まらない場合は tail を付けない）。
+// maxRunes が 0 以下なら何もしない。
+func Truncate(s string, maxRunes int, tail string) string {
+	if maxRunes <= 0 || utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	budget := maxRunes - utf8.RuneCountInString(tail)
+	if budget < 0 {
+		return s[:cutRunes(s, maxRunes)]
+	}
+	return s[:cutRunes(s, budget)] + tail
+}
+
+// TruncateBytes maxBytes バイト以内に切り詰める（TEXT 型などバイト数で上限がある列用）
+//
+// 書記素クラスタの途中では切らないため、結果は常に正しい UTF-8 になる。
+func TruncateBytes(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+	end := 0
+	state := -1
+	for rest := s; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if end+len(cluster) > maxBytes {
+			break
+		}
+		end += len(cluster)
+	}
+	return s[:end]
+}
+
+// cutRunes 先頭から n 文字以内で切れる書記素クラスタの境目（バイト位置）
+func cutRunes(s string, n int) int {
+	end, runes := 0, 0
+	state := -1
+	for rest := s; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		runes += utf8.RuneCountInString(cluster)
+		if runes > n {
+			break
+		}
+		end += len(cluster)
+	}
+	return end
+}
+
+// Width 等幅フォントでの表示幅（全角文字・絵文字は 2）
+func Width(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// TruncateWidth 表示幅 maxWidth 以内に切り詰める（tail を含めて収める。収まらない場合は tail を付けない）
+func TruncateWidth(s string, maxWidth int, tail string) string {
+	if maxWidth <= 0 || Width(s) <= maxWidth {
+		return s
+	}
+	budget := maxWidth - Width(tail)
+	if budget < 0 {
+		budget, tail = maxWidth, ""
+	}
+	end, width := 0, 0
+	state := -1
+	for rest := s; rest != ""; {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if width+w > budget {
+			break
+		}
+		end += len(cluster)
+		width += w
+	}
+	return s[:end] + tail
+}
+
+// PadRight 表示幅が width になるよう末尾を空白で埋める（fmt の %-*s は全角文字の幅を数えないため）
+func PadRight(s string, width int) string {
+	if w := Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+// internal/textutil/truncate_test.go
+package textutil
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		maxRunes int
+		tail     string
+		want     string
+	}{
+		{"上限以内", "abc", 3, Ellipsis, "abc"},
+		{"上限 0 は切り詰めない", "abcdef", 0, Ellipsis, "abcdef"},
+		{"tail を含めて収める", "abcdef", 5, Ellipsis, "ab..."},
+		{"tail なし", "abcdef", 4, "", "abcd"},
+		{"日本語は文字数で数える", "データベース設計", 5, "…", "データベ…"},
+		{"tail より短い上限は tail を付けない", "abcdef", 1, Ellipsis, "a"},
+		{"tail と同じ上限", "abcdef", 3, Ellipsis, "..."},
+		{"結合文字の途中で切らない", "cafe\u0301s", 4, "", "caf"},
+		{"ZWJ 連結の絵文字の途中で切らない", "a👨‍👩‍👧b", 3, "", "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.s, tt.maxRunes, tt.tail)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d, %q) = %q, 期待値 %q", tt.s, tt.maxRunes, tt.tail, got, tt.want)
+			}
+			if tt.maxRunes > 0 && utf8.RuneCountInString(got) > tt.maxRunes {
+				t.Errorf("Truncate(%q, %d, %q) = %q が上限を超えています", tt.s, tt.maxRunes, tt.tail, got)
+			}
+		})
+	}
+}
+
+func TestTruncateBytes(t *testing.T) {
+	tests := []struct {
+		s        string
+		maxBytes int
+		want     string
+	}{
+		{"abc", 3, "abc"},
+		{"abcdef", 4, "abcd"},
+		{"あいう", 7, "あい"}, // 1文字3バイト
+		{"あいう", 2, ""},
+		{"e\u0301x", 2, ""}, // e + 結合文字（計3バイト）の途中では切らない
+	}
+	for _, tt := range tests {
+		got := TruncateBytes(tt.s, tt.maxBytes)
+		if got != tt.want {
+			t.Errorf("TruncateBytes(%q, %d) = %q, 期待値 %q", tt.s, tt.maxBytes, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("TruncateBytes(%q, %d) = %q が正しい UTF-8 ではありません", tt.s, tt.maxBytes, got)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s        string
+		maxWidth int
+		tail     string
+		want     string
+	}{
+		{"abc", 3, Ellipsis, "abc"},
+		{"日本語テキスト", 7, "…", "日本語…"},
+		{"日本語", 3, "", "日"},       // 全角の途中では切らない
+		{"日本語", 2, Ellipsis, "日"}, // tail が収まらない場合は付けない
+	}
+	for _, tt := range tests {
+		if got := TruncateWidth(tt.s, tt.maxWidth, tt.tail); got != tt.want {
+			t.Errorf("TruncateWidth(%q, %d, %q) = %q, 期待値 %q", tt.s, tt.maxWidth, tt.tail, got, tt.want)
+		}
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	if got := PadRight("日本", 6); got != "日本  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadRight("abcdef", 3); got != "abcdef" {
+		t.Errorf("PadRight = %q", got)
+	}
+}